@@ -0,0 +1,190 @@
+package testthings
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+)
+
+// Failure is a failure recorded by a Collector along with where it happened.
+type Failure struct {
+	Message string
+	File    string
+	Line    int
+}
+
+func (f Failure) String() string {
+	if f.File == "" {
+		return f.Message
+	}
+	return fmt.Sprintf("%s:%d: %s", filepath.Base(f.File), f.Line, f.Message)
+}
+
+// Collector is a Terminator that records failures instead of stopping the
+// test, so that many failures can be seen at once. The recorded failures are
+// reported together through the parent when Report is called, or when the
+// parent cleans up. Any helper that only needs a Terminator works with a
+// Collector as-is.
+type Collector struct {
+	parent Terminator
+
+	mu        sync.Mutex
+	failures  []Failure
+	helpers   map[string]struct{}
+	cleanups  []func()
+	cleanedUp bool
+}
+
+// Collect creates a Collector reporting to the parent. The collected failures
+// are reported when the parent cleans up, if it's a Cleanuper. A nil parent
+// causes Report to panic with the failures instead.
+func Collect(parent Terminator) *Collector {
+	c := &Collector{parent: parent, helpers: map[string]struct{}{}}
+	if cleanuper, ok := parent.(Cleanuper); ok {
+		cleanuper.Cleanup(c.Report)
+	}
+	return c
+}
+
+// Fatal records the failure and returns, unlike testing.TB's Fatal.
+func (c *Collector) Fatal(args ...any) {
+	c.record(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+// Fatalf records the formatted failure and returns.
+func (c *Collector) Fatalf(format string, args ...any) {
+	c.record(fmt.Sprintf(format, args...))
+}
+
+// Error records the failure.
+func (c *Collector) Error(args ...any) {
+	c.record(strings.TrimSuffix(fmt.Sprintln(args...), "\n"))
+}
+
+// Errorf records the formatted failure.
+func (c *Collector) Errorf(format string, args ...any) {
+	c.record(fmt.Sprintf(format, args...))
+}
+
+// Helper marks the calling function as a helper, its callers will be recorded
+// as the call site of failures instead.
+func (c *Collector) Helper() {
+	pc, _, _, ok := runtime.Caller(1)
+	if !ok {
+		return
+	}
+	fn := runtime.FuncForPC(pc)
+	if fn == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.helpers[fn.Name()] = struct{}{}
+}
+
+// Log logs through the parent, if it's a Logger.
+func (c *Collector) Log(args ...any) {
+	if logger, ok := c.parent.(Logger); ok {
+		if th, ok := c.parent.(interface{ Helper() }); ok {
+			th.Helper()
+		}
+		logger.Log(args...)
+	}
+}
+
+// Cleanup registers the function with the parent, if it's a Cleanuper.
+// Otherwise, the function is called on the next Report.
+func (c *Collector) Cleanup(fn func()) {
+	if cleanuper, ok := c.parent.(Cleanuper); ok {
+		cleanuper.Cleanup(fn)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.cleanups = append(c.cleanups, fn)
+}
+
+// Failed reports whether any failures have been collected and not yet
+// reported.
+func (c *Collector) Failed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.failures) > 0
+}
+
+// Failures returns the collected failures that have not yet been reported.
+func (c *Collector) Failures() []Failure {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]Failure(nil), c.failures...)
+}
+
+// Report is a checkpoint that reports the collected failures all together
+// through the parent, using its Error method if it has one and otherwise
+// Fatal. The failures are cleared once reported so that the Collector may be
+// used again.
+func (c *Collector) Report() {
+	if th, ok := c.parent.(interface{ Helper() }); ok {
+		th.Helper()
+	}
+
+	c.mu.Lock()
+	failures := c.failures
+	cleanups := c.cleanups
+	c.failures, c.cleanups = nil, nil
+	c.mu.Unlock()
+
+	// run like testing.TB does, last registered first.
+	for i := len(cleanups) - 1; i >= 0; i-- {
+		cleanups[i]()
+	}
+
+	if len(failures) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, len(failures)+1)
+	lines = append(lines, fmt.Sprintf("collected %d failures:", len(failures)))
+	for _, f := range failures {
+		lines = append(lines, "\t"+strings.ReplaceAll(f.String(), "\n", "\n\t\t"))
+	}
+	msg := strings.Join(lines, "\n")
+
+	switch parent := c.parent.(type) {
+	case nil:
+		panic(msg)
+	case interface{ Error(...any) }:
+		parent.Error(msg)
+	default:
+		parent.Fatal(msg)
+	}
+}
+
+// record adds the failure at its call site: the first caller of the
+// Collector's method that isn't marked as a helper.
+func (c *Collector) record(msg string) {
+	var pcs [64]uintptr
+	// skip runtime.Callers, record and the Collector's method.
+	n := runtime.Callers(3, pcs[:])
+	frames := runtime.CallersFrames(pcs[:n])
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	failure := Failure{Message: msg}
+	for {
+		frame, more := frames.Next()
+		if _, helper := c.helpers[frame.Function]; !helper {
+			failure.File, failure.Line = frame.File, frame.Line
+			break
+		}
+		if !more {
+			break
+		}
+	}
+	c.failures = append(c.failures, failure)
+}
@@ -0,0 +1,80 @@
+package testthings_test
+
+import (
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jahkeup/testthings"
+	"github.com/jahkeup/testthings/must"
+	"github.com/jahkeup/testthings/testerr"
+)
+
+type reported struct {
+	errors   []string
+	fatals   []string
+	cleanups []func()
+}
+
+func (r *reported) Error(args ...any) { r.errors = append(r.errors, args[0].(string)) }
+func (r *reported) Fatal(args ...any) { r.fatals = append(r.fatals, args[0].(string)) }
+func (r *reported) Cleanup(fn func()) { r.cleanups = append(r.cleanups, fn) }
+
+type fatalOnly struct {
+	fatals []string
+}
+
+func (f *fatalOnly) Fatal(args ...any) { f.fatals = append(f.fatals, args[0].(string)) }
+
+func TestCollector(t *testing.T) {
+	parent := &reported{}
+	c := testthings.Collect(parent)
+	require.Len(t, parent.cleanups, 1, "should report on cleanup")
+
+	must.Must[int](c, func() (int, error) { return 0, testerr.Expected })
+	_, _, line, _ := runtime.Caller(0)
+	c.Fatal("second", "failure")
+
+	assert.True(t, c.Failed())
+	failures := c.Failures()
+	require.Len(t, failures, 2, "should keep going after Fatal")
+	assert.Equal(t, "collector_test.go", failures[0].String()[:len("collector_test.go")])
+	assert.Equal(t, line-1, failures[0].Line, "should skip helper frames")
+	assert.Equal(t, line+1, failures[1].Line)
+	assert.Equal(t, "second failure", failures[1].Message)
+	assert.Empty(t, parent.errors, "should not report until checkpoint")
+
+	c.Report()
+	require.Len(t, parent.errors, 1, "should report all together")
+	assert.Contains(t, parent.errors[0], "collected 2 failures")
+	assert.Contains(t, parent.errors[0], testerr.Expected.Error())
+	assert.Contains(t, parent.errors[0], "second failure")
+	assert.False(t, c.Failed(), "should clear reported failures")
+
+	parent.cleanups[0]()
+	assert.Len(t, parent.errors, 1, "should not report again without failures")
+}
+
+func TestCollector_fatalParent(t *testing.T) {
+	parent := &fatalOnly{}
+	c := testthings.Collect(parent)
+
+	cleaned := false
+	c.Cleanup(func() { cleaned = true })
+	c.Fatalf("failed %d", 1)
+	c.Report()
+
+	assert.True(t, cleaned, "should run cleanups without a Cleanuper parent")
+	require.Len(t, parent.fatals, 1)
+	assert.Contains(t, parent.fatals[0], "failed 1")
+}
+
+func TestCollector_nilParent(t *testing.T) {
+	c := testthings.Collect(nil)
+	c.Report()
+
+	c.Error("oops")
+	assert.PanicsWithValue(t, "collected 1 failures:\n\t"+c.Failures()[0].String(), c.Report)
+}
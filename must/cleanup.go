@@ -0,0 +1,143 @@
+package must
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/jahkeup/testthings"
+)
+
+// MustCleanup is Must for values that need tearing down. When the value has a
+// Shutdown(context.Context) error, Close, or Stop method, that teardown is
+// registered with testingT's Cleanup and any error it returns is reported to
+// testingT. Shutdown is given a context that's alive until the teardown has
+// finished.
+//
+// testingT must support Cleanup (as testing.TB does). When only *T has the
+// teardown method, as with pointer receivers, use MustCleanupPtr: tearing down
+// a copy of the value would leak the original, so MustCleanup fails instead.
+func MustCleanup[T any, F Mustable[T]](testingT testthings.Terminator, mustable F) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	var zero T
+	cleanuper, ok := testingT.(testthings.Cleanuper)
+	if !ok {
+		fail(testingT, fmt.Sprintf("must cleanup! but: %T does not support Cleanup", testingT))
+		return zero
+	}
+
+	typ := reflect.TypeOf(&zero).Elem()
+	if !hasTeardown(typ) && hasTeardown(reflect.PointerTo(typ)) {
+		fail(testingT, fmt.Sprintf("must cleanup! but: teardown of %v is on %v, use MustCleanupPtr", typ, reflect.PointerTo(typ)))
+		return zero
+	}
+
+	ret := mustWithin[T](testingT, nil, 0, mustable)
+	registerTeardown(testingT, cleanuper, ret)
+	return ret
+}
+
+// MustCleanupPtr is MustCleanup for pointer constructors, returning the
+// constructed pointer as is (see MustPtr) and registering its teardown.
+func MustCleanupPtr[T any, F PtrMustable[T]](testingT testthings.Terminator, mustable F) *T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	cleanuper, ok := testingT.(testthings.Cleanuper)
+	if !ok {
+		fail(testingT, fmt.Sprintf("must cleanup! but: %T does not support Cleanup", testingT))
+		return nil
+	}
+
+	ret := MustPtr[T](testingT, mustable)
+	registerTeardown(testingT, cleanuper, ret)
+	return ret
+}
+
+// teardownTypes are the teardown methods registerTeardown recognizes.
+var teardownTypes = []reflect.Type{
+	reflect.TypeOf((*interface{ Shutdown(context.Context) error })(nil)).Elem(),
+	reflect.TypeOf((*io.Closer)(nil)).Elem(),
+	reflect.TypeOf((*interface{ Close() })(nil)).Elem(),
+	reflect.TypeOf((*interface{ Stop() error })(nil)).Elem(),
+	reflect.TypeOf((*interface{ Stop() })(nil)).Elem(),
+}
+
+// hasTeardown reports whether values of the type have a teardown method.
+func hasTeardown(t reflect.Type) bool {
+	for _, teardownType := range teardownTypes {
+		if t.Implements(teardownType) {
+			return true
+		}
+	}
+	return false
+}
+
+// registerTeardown registers the value's teardown, if it has one.
+func registerTeardown(testingT testthings.Terminator, cleanuper testthings.Cleanuper, v any) {
+	if isNil(v) {
+		return
+	}
+
+	var teardown func() error
+	switch x := v.(type) {
+	case interface{ Shutdown(context.Context) error }:
+		// the context's own cleanup is registered first and so runs after
+		// the teardown.
+		ctx := testthings.C(cleanuper)
+		teardown = func() error { return x.Shutdown(ctx) }
+	case io.Closer:
+		teardown = x.Close
+	case interface{ Close() }:
+		teardown = func() error {
+			x.Close()
+			return nil
+		}
+	case interface{ Stop() error }:
+		teardown = x.Stop
+	case interface{ Stop() }:
+		teardown = func() error {
+			x.Stop()
+			return nil
+		}
+	default:
+		return
+	}
+
+	cleanuper.Cleanup(func() {
+		if err := teardown(); err != nil {
+			msg := fmt.Sprintf("must cleanup! but: teardown %T: %v", v, err)
+			// prefer to continue with the other cleanups, if possible.
+			if te, ok := testingT.(interface {
+				Error(args ...any)
+			}); ok {
+				te.Error(msg)
+				return
+			}
+			testingT.Fatal(msg)
+		}
+	})
+}
+
+// isNil reports whether v is nil or holds a nil value.
+func isNil(v any) bool {
+	if v == nil {
+		return true
+	}
+
+	rv := reflect.ValueOf(v)
+	switch rv.Kind() {
+	case reflect.Chan, reflect.Func, reflect.Interface, reflect.Map, reflect.Pointer, reflect.Slice:
+		return rv.IsNil()
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+package must_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jahkeup/testthings/must"
+	"github.com/jahkeup/testthings/testerr"
+)
+
+// cleanups records Cleanup functions to be run by the test.
+type cleanups struct {
+	fatals
+	funcs []func()
+}
+
+func (c *cleanups) Cleanup(fn func()) {
+	c.funcs = append(c.funcs, fn)
+}
+
+func (c *cleanups) run() {
+	for i := len(c.funcs) - 1; i >= 0; i-- {
+		c.funcs[i]()
+	}
+}
+
+type stopper struct {
+	stopped bool
+}
+
+func (s *stopper) Stop() {
+	s.stopped = true
+}
+
+// server has its teardown on the pointer, like most constructed values.
+type server struct {
+	closed bool
+}
+
+func (s *server) Close() error {
+	s.closed = true
+	return nil
+}
+
+type shutdowner struct {
+	ctxErr error
+	err    error
+}
+
+func (s *shutdowner) Shutdown(ctx context.Context) error {
+	s.ctxErr = ctx.Err()
+	return s.err
+}
+
+func TestMustCleanup(t *testing.T) {
+	t.Run("closer", func(t *testing.T) {
+		var f *os.File
+		t.Run("open", func(t *testing.T) {
+			f = must.MustCleanup[*os.File](t, func() (*os.File, error) {
+				return os.Create(filepath.Join(t.TempDir(), "file"))
+			})
+		})
+		if err := f.Close(); err == nil {
+			t.Error("file should have been closed by cleanup")
+		}
+	})
+
+	t.Run("server", func(t *testing.T) {
+		var srv *httptest.Server
+		t.Run("serve", func(t *testing.T) {
+			srv = must.MustCleanup[*httptest.Server](t, func() *httptest.Server {
+				return httptest.NewServer(http.NotFoundHandler())
+			})
+		})
+		if _, err := http.Get(srv.URL); err == nil {
+			t.Error("server should have been closed by cleanup")
+		}
+	})
+
+	t.Run("stopper", func(t *testing.T) {
+		c := &cleanups{}
+		s := must.MustCleanup[*stopper](c, func() *stopper { return &stopper{} })
+		c.run()
+		if !s.stopped {
+			t.Error("should have been stopped")
+		}
+	})
+
+	t.Run("shutdown", func(t *testing.T) {
+		c := &cleanups{}
+		s := must.MustCleanup[*shutdowner](c, func() *shutdowner {
+			return &shutdowner{err: testerr.Expected}
+		})
+		c.run()
+		if s.ctxErr != nil {
+			t.Errorf("shutdown context should be alive, was: %v", s.ctxErr)
+		}
+		if len(c.msgs) != 1 || !strings.Contains(c.msgs[0], testerr.Expected.Error()) {
+			t.Errorf("should report teardown error, got: %q", c.msgs)
+		}
+	})
+
+	t.Run("pointer receiver", func(t *testing.T) {
+		c := &cleanups{}
+		must.MustCleanup[server](c, func() (*server, error) { return &server{}, nil })
+		if len(c.msgs) != 1 || !strings.Contains(c.msgs[0], "MustCleanupPtr") {
+			t.Errorf("should refuse to tear down a copy, got: %q", c.msgs)
+		}
+		if len(c.funcs) != 0 {
+			t.Errorf("should not register teardown, got %d", len(c.funcs))
+		}
+	})
+
+	t.Run("ptr", func(t *testing.T) {
+		c := &cleanups{}
+		s := must.MustCleanupPtr[server](c, func() (*server, error) { return &server{}, nil })
+		c.run()
+		if len(c.msgs) != 0 {
+			t.Errorf("should not fail, got: %q", c.msgs)
+		}
+		if !s.closed {
+			t.Error("constructed value should have been closed")
+		}
+	})
+
+	t.Run("no cleanup", func(t *testing.T) {
+		f := &fatals{}
+		must.MustCleanup[*stopper](f, func() *stopper { return &stopper{} })
+		if len(f.msgs) != 1 {
+			t.Errorf("should have failed once, got: %q", f.msgs)
+		}
+	})
+}
@@ -0,0 +1,207 @@
+package must
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jahkeup/testthings"
+)
+
+var (
+	textUnmarshalerType = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	durationType        = reflect.TypeOf(time.Duration(0))
+	recordType          = reflect.TypeOf([]string(nil))
+)
+
+// FromCSV will parse the given csv text into records of type T, either
+// []string or a struct. The first record of the text is the header for
+// structs, mapping its columns to fields by their `csv` tag or, without one,
+// their name. If the text does not parse, the test will be failed.
+// Alternatively, this function may be used in initialization contexts by
+// passing nil for the testingT object in which case errors will cause the
+// program to panic at runtime.
+//
+// Of the options, DisallowUnknownFields rejects columns without a field and
+// RequireFields requires a column for each field tagged `must:"required"`.
+func FromCSV[T any](testingT testthings.Terminator, text []byte, opts ...DecodeOption) []T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	return Must[[]T](testingT, func() []T {
+		return decodeText[[]T](testingT, "", csvFormat, text, opts...)
+	})
+}
+
+// decodeCSV decodes the CSV text into out, a pointer to a slice of either
+// []string (the raw records) or structs. Struct fields are mapped to the
+// header's columns by their `csv` tag or, without one, their name. When the
+// DisallowUnknownFields option is given, columns must map to a field; with
+// RequireFields, fields tagged `must:"required"` must have a column.
+func decodeCSV(text []byte, out any, opts ...DecodeOption) error {
+	var options decodeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Pointer || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("csv decode into %T, need a pointer to a slice", out)
+	}
+	slice = slice.Elem()
+	slice.SetLen(0)
+
+	elemType := slice.Type().Elem()
+	if elemType != recordType && elemType.Kind() != reflect.Struct {
+		return fmt.Errorf("csv decode into %T, need a slice of []string or structs", out)
+	}
+
+	r := csv.NewReader(bytes.NewReader(text))
+
+	var (
+		header  []string
+		columns [][]int
+	)
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if elemType == recordType {
+			slice.Set(reflect.Append(slice, reflect.ValueOf(record)))
+			continue
+		}
+
+		if header == nil {
+			header = record
+			columns, err = csvColumns(elemType, header, options)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+
+		elem := reflect.New(elemType).Elem()
+		for col, fieldIndex := range columns {
+			if fieldIndex == nil || col >= len(record) {
+				continue
+			}
+			if err := setCSVField(elem.FieldByIndex(fieldIndex), record[col]); err != nil {
+				line, column := r.FieldPos(col)
+				return fmt.Errorf("line %d, column %d (%q): %w", line, column, header[col], err)
+			}
+		}
+		slice.Set(reflect.Append(slice, elem))
+	}
+}
+
+// csvColumns maps the header's columns to the index of their struct field, nil
+// when the column has no field.
+func csvColumns(t reflect.Type, header []string, options decodeOptions) ([][]int, error) {
+	fields := map[string][]int{}
+	var names []string
+	required := map[string]bool{}
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		name, _, _ := strings.Cut(field.Tag.Get("csv"), ",")
+		if name == "-" {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+		fields[strings.ToLower(name)] = field.Index
+		names = append(names, name)
+		if field.Tag.Get("must") == "required" {
+			required[strings.ToLower(name)] = true
+		}
+	}
+
+	columns := make([][]int, len(header))
+	for i, column := range header {
+		key := strings.ToLower(strings.TrimSpace(column))
+		index, ok := fields[key]
+		if !ok && options.disallowUnknownFields {
+			return nil, fmt.Errorf("unknown column %q, %v has columns: %s", column, t, strings.Join(names, ", "))
+		}
+		columns[i] = index
+		delete(required, key)
+	}
+
+	if options.requireFields {
+		for _, name := range names {
+			if required[strings.ToLower(name)] {
+				return nil, fmt.Errorf("missing required column %q", name)
+			}
+		}
+	}
+
+	return columns, nil
+}
+
+// setCSVField parses the text into the field. Empty text leaves the field's
+// zero value.
+func setCSVField(field reflect.Value, text string) error {
+	if text == "" {
+		return nil
+	}
+
+	if field.Kind() == reflect.Pointer {
+		field.Set(reflect.New(field.Type().Elem()))
+		field = field.Elem()
+	}
+
+	if field.Addr().Type().Implements(textUnmarshalerType) {
+		return field.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(text))
+	}
+
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(text)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	var err error
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(text)
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(text)
+		field.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(text, 10, field.Type().Bits())
+		field.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		n, err = strconv.ParseUint(text, 10, field.Type().Bits())
+		field.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var n float64
+		n, err = strconv.ParseFloat(text, field.Type().Bits())
+		field.SetFloat(n)
+	default:
+		err = fmt.Errorf("unsupported field type %v", field.Type())
+	}
+
+	return err
+}
@@ -0,0 +1,147 @@
+package must
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jahkeup/testthings"
+)
+
+// decodeFunc decodes the text into out (a pointer).
+type decodeFunc func(text []byte, out any, opts ...DecodeOption) error
+
+// fileFormat is a decodable format, named for messages.
+type fileFormat struct {
+	name   string
+	decode decodeFunc
+}
+
+// fileFormats are the decodable formats, by file extension.
+var fileFormats = map[string]fileFormat{
+	".json":   jsonFormat,
+	".jsonl":  jsonLinesFormat,
+	".ndjson": jsonLinesFormat,
+	".xml":    xmlFormat,
+	".yaml":   yamlFormat,
+	".yml":    yamlFormat,
+	".gob":    gobFormat,
+	".csv":    csvFormat,
+}
+
+var (
+	jsonFormat      = fileFormat{"json", decodeJSON}
+	jsonLinesFormat = fileFormat{"json lines", decodeJSONLines}
+	xmlFormat       = fileFormat{"xml", decodeXML}
+	yamlFormat      = fileFormat{"yaml", decodeYAML}
+	gobFormat       = fileFormat{"gob", decodeGob}
+	csvFormat       = fileFormat{"csv", decodeCSV}
+)
+
+// FromFile reads and decodes the file into an output object, picking the
+// decoder by the file's extension: .json, .jsonl (or .ndjson), .xml, .yaml (or
+// .yml), .gob, or .csv. JSON Lines and CSV files are decoded into slices, see
+// FromJSONL and FromCSV. The options are used by the decoders that support
+// them.
+//
+// If the file cannot be read or decoded, the test will be failed with the
+// file's path. Alternatively, this function may be used in initialization
+// contexts by passing nil for the testingT object in which case errors will
+// cause the program to panic at runtime.
+func FromFile[T any](testingT testthings.Terminator, filePath string, opts ...DecodeOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	return Must[T](testingT, func() T {
+		var out T
+		text, err := os.ReadFile(filePath)
+		if err != nil {
+			fail(testingT, fmt.Sprintf("cannot read file for %T: %v", out, err))
+			return out
+		}
+		return fromFileText[T](testingT, filePath, text, opts...)
+	})
+}
+
+// FromTestdata is FromFile for the file at the name within the package's
+// testdata directory.
+func FromTestdata[T any](testingT testthings.Terminator, name string, opts ...DecodeOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	return FromFile[T](testingT, filepath.Join("testdata", filepath.FromSlash(name)), opts...)
+}
+
+// FromFS is FromFile for the file at the name within fsys, like an embed.FS or
+// a skeletonfs source.
+func FromFS[T any](testingT testthings.Terminator, fsys fs.FS, name string, opts ...DecodeOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	return Must[T](testingT, func() T {
+		var out T
+		text, err := fs.ReadFile(fsys, name)
+		if err != nil {
+			fail(testingT, fmt.Sprintf("cannot read file for %T: %v", out, err))
+			return out
+		}
+		return fromFileText[T](testingT, name, text, opts...)
+	})
+}
+
+// fromFileText decodes the file's text with the decoder for its extension.
+func fromFileText[T any](testingT testthings.Terminator, name string, text []byte, opts ...DecodeOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	ext := strings.ToLower(path.Ext(filepath.ToSlash(name)))
+	format, ok := fileFormats[ext]
+	if !ok {
+		var zero T
+		fail(testingT, fmt.Sprintf("%s: cannot decode %q files, supported: %s", name, ext, supportedExts()))
+		return zero
+	}
+
+	return decodeText[T](testingT, name+": ", format, text, opts...)
+}
+
+// decodeText decodes the text in the format, failing with the message prefix
+// when it cannot.
+func decodeText[T any](testingT testthings.Terminator, prefix string, format fileFormat, text []byte, opts ...DecodeOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	out := new(T)
+	if err := format.decode(text, out, opts...); err != nil {
+		fail(testingT, fmt.Sprintf("%scannot unmarshal %s into %T: %v", prefix, format.name, out, err))
+	}
+	return *out
+}
+
+func supportedExts() string {
+	exts := make([]string, 0, len(fileFormats))
+	for ext := range fileFormats {
+		exts = append(exts, ext)
+	}
+	sort.Strings(exts)
+	return strings.Join(exts, ", ")
+}
@@ -0,0 +1,147 @@
+package must_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/jahkeup/testthings/must"
+)
+
+type person struct {
+	Name string `json:"name" yaml:"name" xml:"name" csv:"name" must:"required"`
+	Age  int    `json:"age" yaml:"age" xml:"age" csv:"age"`
+}
+
+type people struct {
+	Person []person `xml:"person"`
+}
+
+var expectedPeople = []person{
+	{Name: "Ada", Age: 36},
+	{Name: "Grace", Age: 85},
+}
+
+func TestFromTestdata(t *testing.T) {
+	for _, name := range []string{"people.json", "people.jsonl", "people.yaml", "people.csv"} {
+		t.Run(name, func(t *testing.T) {
+			actual := must.FromTestdata[[]person](t, "fixtures/"+name, must.StrictJSON())
+			if !reflect.DeepEqual(actual, expectedPeople) {
+				t.Errorf("unexpected people: %#v", actual)
+			}
+		})
+	}
+
+	t.Run("people.xml", func(t *testing.T) {
+		actual := must.FromTestdata[people](t, "fixtures/people.xml")
+		if !reflect.DeepEqual(actual.Person, expectedPeople) {
+			t.Errorf("unexpected people: %#v", actual)
+		}
+	})
+
+	t.Run("people.gob", func(t *testing.T) {
+		var buf bytes.Buffer
+		if err := gob.NewEncoder(&buf).Encode(expectedPeople); err != nil {
+			t.Fatal(err)
+		}
+		path := filepath.Join(t.TempDir(), "people.gob")
+		if err := os.WriteFile(path, buf.Bytes(), 0600); err != nil {
+			t.Fatal(err)
+		}
+
+		actual := must.FromFile[[]person](t, path)
+		if !reflect.DeepEqual(actual, expectedPeople) {
+			t.Errorf("unexpected people: %#v", actual)
+		}
+	})
+}
+
+func TestFromFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"config/app.yml": &fstest.MapFile{Data: []byte("name: Ada\nage: 36\n")},
+	}
+
+	actual := must.FromFS[person](t, fsys, "config/app.yml")
+	if actual != expectedPeople[0] {
+		t.Errorf("unexpected person: %#v", actual)
+	}
+}
+
+func TestFromFile_failures(t *testing.T) {
+	testcases := map[string]struct {
+		load     func(f *fatals)
+		expected []string
+	}{
+		"missing": {
+			load: func(f *fatals) {
+				must.FromTestdata[person](f, "fixtures/missing.json")
+			},
+			expected: []string{filepath.Join("testdata", "fixtures", "missing.json")},
+		},
+		"unsupported": {
+			load: func(f *fatals) {
+				must.FromFS[person](f, fstest.MapFS{"a.toml": &fstest.MapFile{}}, "a.toml")
+			},
+			expected: []string{"a.toml", `cannot decode ".toml" files`},
+		},
+		"jsonl line": {
+			load: func(f *fatals) {
+				must.FromTestdata[[]person](f, "fixtures/bad.jsonl")
+			},
+			expected: []string{"bad.jsonl: cannot unmarshal json lines", "line 2, column 26"},
+		},
+		"csv field": {
+			load: func(f *fatals) {
+				must.FromFS[[]person](f, fstest.MapFS{
+					"p.csv": &fstest.MapFile{Data: []byte("name,age\nAda,old\n")},
+				}, "p.csv")
+			},
+			expected: []string{"p.csv: cannot unmarshal csv", `line 2, column 5 ("age")`, `parsing "old"`},
+		},
+		"csv unknown column": {
+			load: func(f *fatals) {
+				must.FromFS[[]person](f, fstest.MapFS{
+					"p.csv": &fstest.MapFile{Data: []byte("name,agee\nAda,36\n")},
+				}, "p.csv", must.DisallowUnknownFields())
+			},
+			expected: []string{`unknown column "agee"`},
+		},
+		"csv required column": {
+			load: func(f *fatals) {
+				must.FromFS[[]person](f, fstest.MapFS{
+					"p.csv": &fstest.MapFile{Data: []byte("age\n36\n")},
+				}, "p.csv", must.RequireFields())
+			},
+			expected: []string{`missing required column "name"`},
+		},
+		"yaml unknown field": {
+			load: func(f *fatals) {
+				must.FromFS[person](f, fstest.MapFS{
+					"p.yaml": &fstest.MapFile{Data: []byte("name: Ada\naeg: 36\n")},
+				}, "p.yaml", must.DisallowUnknownFields())
+			},
+			expected: []string{"p.yaml: cannot unmarshal yaml", "line 2", "aeg"},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			f := &fatals{}
+			tc.load(f)
+			if len(f.msgs) != 1 {
+				t.Fatalf("should have failed once, got %q", f.msgs)
+			}
+			t.Log(f.msgs[0])
+			for _, expected := range tc.expected {
+				if !strings.Contains(f.msgs[0], expected) {
+					t.Errorf("should contain %q", expected)
+				}
+			}
+		})
+	}
+}
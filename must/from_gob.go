@@ -0,0 +1,29 @@
+package must
+
+import (
+	"bytes"
+	"encoding/gob"
+
+	"github.com/jahkeup/testthings"
+)
+
+// FromGob will parse the given gob encoded data into an output object. If the object
+// does not parse, the test will be failed. Alternatively, this function may be
+// used in initialization contexts by passing nil for the testingT object in
+// which case errors will cause the program to panic at runtime.
+func FromGob[T any](testingT testthings.Terminator, data []byte) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	return Must[T](testingT, func() T {
+		return decodeText[T](testingT, "", gobFormat, data)
+	})
+}
+
+// decodeGob decodes the gob encoded data into out (a pointer).
+func decodeGob(data []byte, out any, _ ...DecodeOption) error {
+	return gob.NewDecoder(bytes.NewReader(data)).Decode(out)
+}
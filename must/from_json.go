@@ -1,17 +1,70 @@
 package must
 
 import (
-	"encoding/json"
-	"fmt"
-
 	"github.com/jahkeup/testthings"
 )
 
+// DecodeOption configures how text is decoded. Each option lists the formats
+// that honor it, the decoders of other formats ignore it.
+type DecodeOption func(*decodeOptions)
+
+type decodeOptions struct {
+	disallowUnknownFields bool
+	useNumber             bool
+	rejectDuplicateKeys   bool
+	requireFields         bool
+}
+
+// DisallowUnknownFields fails decoding when an object has a key (or a CSV
+// record a column) that doesn't match a field of the destination struct. JSON,
+// JSON Lines, YAML, and CSV honor it.
+func DisallowUnknownFields() DecodeOption {
+	return func(o *decodeOptions) { o.disallowUnknownFields = true }
+}
+
+// UseNumber decodes numbers into interface values as json.Number instead of
+// float64. Only JSON (and JSON Lines) honors it.
+func UseNumber() DecodeOption {
+	return func(o *decodeOptions) { o.useNumber = true }
+}
+
+// RejectTrailingData fails decoding when there's more than whitespace after
+// the decoded value. JSON (and JSON Lines) decoding always does, as
+// json.Unmarshal does, so the option only states it.
+func RejectTrailingData() DecodeOption {
+	return func(*decodeOptions) {}
+}
+
+// RejectDuplicateKeys fails decoding when an object has the same key more than
+// once. Only JSON (and JSON Lines) honors it.
+func RejectDuplicateKeys() DecodeOption {
+	return func(o *decodeOptions) { o.rejectDuplicateKeys = true }
+}
+
+// RequireFields fails decoding when the text is missing a struct field tagged
+// with `must:"required"`. JSON, JSON Lines, and CSV honor it.
+func RequireFields() DecodeOption {
+	return func(o *decodeOptions) { o.requireFields = true }
+}
+
+// StrictJSON enables all of the options that reject questionable JSON text:
+// unknown fields, duplicate keys, and missing required fields, on top of the
+// trailing data that's always rejected.
+func StrictJSON() DecodeOption {
+	return func(o *decodeOptions) {
+		DisallowUnknownFields()(o)
+		RejectDuplicateKeys()(o)
+		RequireFields()(o)
+	}
+}
+
 // FromJSON will parse the given json text into an output object. If the object
 // does not parse, the test will be failed. Alternatively, this function may be
 // used in initialization contexts by passing nil for the testingT object in
 // which case errors will cause the program to panic at runtime.
-func FromJSON[T any](testingT testthings.Terminator, text []byte) T {
+//
+// Failures are reported with the line and column of the offending text.
+func FromJSON[T any](testingT testthings.Terminator, text []byte, opts ...DecodeOption) T {
 	if th, ok := testingT.(interface {
 		Helper()
 	}); ok {
@@ -19,15 +72,6 @@ func FromJSON[T any](testingT testthings.Terminator, text []byte) T {
 	}
 
 	return Must[T](testingT, func() T {
-		out := new(T)
-		if err := json.Unmarshal(text, out); err != nil {
-			msg := fmt.Sprintf("cannot unmarshal json into %T: %v", out, err)
-			if testingT == nil {
-				panic(msg)
-			}
-			testingT.Fatal(msg)
-		}
-
-		return *out
+		return decodeText[T](testingT, "", jsonFormat, text, opts...)
 	})
 }
@@ -0,0 +1,232 @@
+package must
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jahkeup/testthings"
+)
+
+// FromJSONPointer will parse the value at the RFC 6901 JSON Pointer, like
+// "/items/0/name", within the given json text into an output object. Only the
+// pointed to value is decoded. If the pointer cannot be resolved, the test will
+// be failed listing the keys available where resolution stopped.
+// Alternatively, this function may be used in initialization contexts by
+// passing nil for the testingT object in which case errors will cause the
+// program to panic at runtime.
+func FromJSONPointer[T any](testingT testthings.Terminator, text []byte, pointer string, opts ...DecodeOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	if pointer != "" && !strings.HasPrefix(pointer, "/") {
+		var zero T
+		fail(testingT, fmt.Sprintf("cannot resolve json pointer %q: must be empty or start with /", pointer))
+		return zero
+	}
+
+	return fromJSONSegments[T](testingT, text, pointer, parseJSONPointer(pointer), opts...)
+}
+
+// FromJSONPath is FromJSONPointer for a simple dotted path with array indices,
+// like "items[0].name".
+func FromJSONPath[T any](testingT testthings.Terminator, text []byte, path string, opts ...DecodeOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	segments, err := parseJSONPath(path)
+	if err != nil {
+		var zero T
+		fail(testingT, fmt.Sprintf("cannot resolve json path %q: %v", path, err))
+		return zero
+	}
+
+	return fromJSONSegments[T](testingT, text, path, segments, opts...)
+}
+
+func fromJSONSegments[T any](testingT testthings.Terminator, text []byte, path string, segments []string, opts ...DecodeOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	return Must[T](testingT, func() T {
+		out := new(T)
+		start, end, err := locateJSONValue(text, segments)
+		if err != nil {
+			fail(testingT, fmt.Sprintf("cannot resolve %q in json: %v", path, err))
+			return *out
+		}
+
+		if err := decodeJSON(text[start:end], out, opts...); err != nil {
+			// locate the error in the whole text, not just the value
+			var textErr *jsonTextError
+			if errors.As(err, &textErr) {
+				err = newJSONTextError(text, start+textErr.Offset, textErr.Err)
+			}
+			fail(testingT, fmt.Sprintf("cannot unmarshal json at %q into %T: %v", path, out, err))
+		}
+		return *out
+	})
+}
+
+// locateJSONValue finds the start and end offsets of the value at the path
+// segments in the text.
+func locateJSONValue(text []byte, segments []string) (start, end int64, err error) {
+	dec := json.NewDecoder(bytes.NewReader(text))
+	dec.UseNumber()
+
+	start = skipJSONSpace(text, 0)
+	for depth, segment := range segments {
+		at := jsonPointer(segments[:depth])
+
+		tok, err := dec.Token()
+		if err != nil {
+			return 0, 0, locateJSONError(text, dec.InputOffset(), err)
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			var keys []string
+			found := false
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return 0, 0, locateJSONError(text, dec.InputOffset(), err)
+				}
+				key := keyTok.(string)
+				if key == segment {
+					found = true
+					start = skipJSONDelims(text, dec.InputOffset())
+					break
+				}
+				keys = append(keys, key)
+				if err := skipJSONValue(dec); err != nil {
+					return 0, 0, locateJSONError(text, dec.InputOffset(), err)
+				}
+			}
+			if !found {
+				sort.Strings(keys)
+				return 0, 0, fmt.Errorf("key %q not found in object at %q, available keys: %s", segment, at, formatKeys(keys))
+			}
+
+		case json.Delim('['):
+			index, err := parseJSONIndex(segment)
+			if err != nil {
+				return 0, 0, fmt.Errorf("cannot index array at %q with %q: %w", at, segment, err)
+			}
+			found := false
+			n := 0
+			for dec.More() {
+				if n == index {
+					found = true
+					start = skipJSONDelims(text, dec.InputOffset())
+					break
+				}
+				if err := skipJSONValue(dec); err != nil {
+					return 0, 0, locateJSONError(text, dec.InputOffset(), err)
+				}
+				n++
+			}
+			if !found && n == 0 {
+				return 0, 0, fmt.Errorf("index %d out of range in empty array at %q", index, at)
+			}
+			if !found {
+				return 0, 0, fmt.Errorf("index %d out of range in array at %q, available indices: 0 to %d", index, at, n-1)
+			}
+
+		default:
+			return 0, 0, fmt.Errorf("cannot resolve %q in %s at %q", segment, jsonKind(tok), at)
+		}
+	}
+
+	if err := skipJSONValue(dec); err != nil {
+		return 0, 0, locateJSONError(text, dec.InputOffset(), err)
+	}
+	return start, dec.InputOffset(), nil
+}
+
+func skipJSONValue(dec *json.Decoder) error {
+	var skip json.RawMessage
+	return dec.Decode(&skip)
+}
+
+func formatKeys(keys []string) string {
+	if len(keys) == 0 {
+		return "(none)"
+	}
+	quoted := make([]string, len(keys))
+	for i, k := range keys {
+		quoted[i] = strconv.Quote(k)
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// jsonKind names the kind of the value that starts with the token.
+func jsonKind(tok json.Token) string {
+	switch tok.(type) {
+	case string:
+		return "string"
+	case json.Number, float64:
+		return "number"
+	case bool:
+		return "boolean"
+	case nil:
+		return "null"
+	}
+	return fmt.Sprintf("%v", tok)
+}
+
+// parseJSONIndex parses the array index segment, which RFC 6901 requires to be
+// decimal digits without leading zeros.
+func parseJSONIndex(segment string) (int, error) {
+	if segment == "" || (len(segment) > 1 && segment[0] == '0') || strings.TrimLeft(segment, "0123456789") != "" {
+		return 0, errors.New("not an array index")
+	}
+	return strconv.Atoi(segment)
+}
+
+// parseJSONPath splits the dotted path, like "items[0].name", into segments.
+func parseJSONPath(path string) ([]string, error) {
+	var segments []string
+	if path == "" {
+		return segments, nil
+	}
+
+	for _, part := range strings.Split(path, ".") {
+		name, indices, _ := strings.Cut(part, "[")
+		if name == "" && (len(segments) > 0 || indices == "") {
+			return nil, fmt.Errorf("empty key in path")
+		}
+		if name != "" {
+			segments = append(segments, name)
+		}
+		if indices == "" {
+			continue
+		}
+
+		for _, index := range strings.Split("["+indices, "[")[1:] {
+			index, ok := strings.CutSuffix(index, "]")
+			if !ok {
+				return nil, fmt.Errorf("unterminated index in %q", part)
+			}
+			if _, err := parseJSONIndex(index); err != nil {
+				return nil, fmt.Errorf("index %q: %w", index, err)
+			}
+			segments = append(segments, index)
+		}
+	}
+
+	return segments, nil
+}
@@ -0,0 +1,120 @@
+package must_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/jahkeup/testthings/must"
+)
+
+var recordedResponse = []byte(`{
+  "meta": {"request_id": "abc", "page": 1},
+  "data": {
+    "people": [
+      {"name": "Ada", "age": 36},
+      {"name": "Grace", "age": "85"}
+    ],
+    "a/b": {"~c": true}
+  }
+}`)
+
+func TestFromJSONPointer(t *testing.T) {
+	t.Run("subtree", func(t *testing.T) {
+		actual := must.FromJSONPointer[person](t, recordedResponse, "/data/people/0", must.StrictJSON())
+		if actual != expectedPeople[0] {
+			t.Errorf("unexpected person: %#v", actual)
+		}
+	})
+
+	t.Run("escaped", func(t *testing.T) {
+		if !must.FromJSONPointer[bool](t, recordedResponse, "/data/a~1b/~0c") {
+			t.Error("should have resolved the escaped keys")
+		}
+	})
+
+	t.Run("whole", func(t *testing.T) {
+		actual := must.FromJSONPointer[map[string]any](t, recordedResponse, "")
+		if _, ok := actual["meta"]; !ok {
+			t.Errorf("unexpected document: %#v", actual)
+		}
+	})
+}
+
+func TestFromJSONPath(t *testing.T) {
+	actual := must.FromJSONPath[string](t, recordedResponse, "data.people[0].name")
+	if actual != "Ada" {
+		t.Errorf("unexpected name: %q", actual)
+	}
+
+	names := must.FromJSONPath[[]map[string]any](t, []byte(`[[{"name": "Ada"}]]`), "[0]")
+	if !reflect.DeepEqual(names, []map[string]any{{"name": "Ada"}}) {
+		t.Errorf("unexpected names: %#v", names)
+	}
+}
+
+func TestFromJSONPointer_failures(t *testing.T) {
+	testcases := map[string]struct {
+		load     func(f *fatals)
+		expected []string
+	}{
+		"missing key": {
+			load: func(f *fatals) {
+				must.FromJSONPointer[any](f, recordedResponse, "/data/persons")
+			},
+			expected: []string{`key "persons" not found in object at "/data"`, `available keys: "a/b", "people"`},
+		},
+		"out of range": {
+			load: func(f *fatals) {
+				must.FromJSONPath[any](f, recordedResponse, "data.people[2]")
+			},
+			expected: []string{`index 2 out of range in array at "/data/people", available indices: 0 to 1`},
+		},
+		"not an index": {
+			load: func(f *fatals) {
+				must.FromJSONPointer[any](f, recordedResponse, "/data/people/first")
+			},
+			expected: []string{`cannot index array at "/data/people" with "first"`},
+		},
+		"scalar": {
+			load: func(f *fatals) {
+				must.FromJSONPointer[any](f, recordedResponse, "/meta/page/next")
+			},
+			expected: []string{`cannot resolve "next" in number at "/meta/page"`},
+		},
+		"relative": {
+			load: func(f *fatals) {
+				must.FromJSONPointer[any](f, recordedResponse, "meta")
+			},
+			expected: []string{"must be empty or start with /"},
+		},
+		"bad path": {
+			load: func(f *fatals) {
+				must.FromJSONPath[any](f, recordedResponse, "data.people[0")
+			},
+			expected: []string{"unterminated index"},
+		},
+		"decode located": {
+			load: func(f *fatals) {
+				must.FromJSONPath[person](f, recordedResponse, "data.people[1]")
+			},
+			expected: []string{`cannot unmarshal json at "data.people[1]"`, "line 6, column 32"},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			f := &fatals{}
+			tc.load(f)
+			if len(f.msgs) != 1 {
+				t.Fatalf("should have failed once, got %q", f.msgs)
+			}
+			t.Log(f.msgs[0])
+			for _, expected := range tc.expected {
+				if !strings.Contains(f.msgs[0], expected) {
+					t.Errorf("should contain %q", expected)
+				}
+			}
+		})
+	}
+}
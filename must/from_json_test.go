@@ -2,7 +2,9 @@ package must_test
 
 import (
 	_ "embed"
+	"encoding/json"
 	"reflect"
+	"strings"
 	"testing"
 
 	"github.com/jahkeup/testthings/must"
@@ -97,3 +99,101 @@ func TestFromJSON_simple(t *testing.T) {
 		}
 	}
 }
+
+type fixture struct {
+	Name   string         `json:"name" must:"required"`
+	Count  int            `json:"count"`
+	Nested *fixtureNested `json:"nested,omitempty"`
+}
+
+type fixtureNested struct {
+	ID string `json:"id" must:"required"`
+}
+
+func TestFromJSON_options(t *testing.T) {
+	testcases := map[string]struct {
+		json     string
+		opts     []must.DecodeOption
+		expected []string
+	}{
+		"unknown field": {
+			json:     "{\n  \"name\": \"a\",\n  \"cuont\": 1\n}",
+			opts:     []must.DecodeOption{must.DisallowUnknownFields()},
+			expected: []string{"line 3, column 3", `unknown field "cuont"`, `    3 |   "cuont": 1`},
+		},
+		"trailing data": {
+			json:     `{"name": "a"} {"name": "b"}`,
+			expected: []string{"line 1, column 15", "after top-level value"},
+		},
+		"duplicate keys": {
+			json:     "{\"name\": \"a\",\n \"nested\": {\"id\": \"1\", \"id\": \"2\"}}",
+			opts:     []must.DecodeOption{must.RejectDuplicateKeys()},
+			expected: []string{"line 2, column 24", `duplicate key "id" in object at "/nested"`},
+		},
+		"required": {
+			json:     `{"count": 1}`,
+			opts:     []must.DecodeOption{must.RequireFields()},
+			expected: []string{`missing required field "name"`},
+		},
+		"nested required": {
+			json:     `{"name": "a", "nested": {}}`,
+			opts:     []must.DecodeOption{must.StrictJSON()},
+			expected: []string{`missing required field "id"`, `object at "/nested"`},
+		},
+		"syntax": {
+			json:     "{\n\t\"name\": \"a\",,\n}",
+			expected: []string{"line 2, column 14", "invalid character ','"},
+		},
+		"type": {
+			json:     "{\"name\": \"a\",\n  \"count\": \"one\"}",
+			expected: []string{"line 2, column 12", "cannot unmarshal string"},
+		},
+		"truncated": {
+			json:     `{"name": "a"`,
+			expected: []string{"line 1, column 13", "unexpected EOF"},
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			f := &fatals{}
+			must.FromJSON[fixture](f, []byte(tc.json), tc.opts...)
+			if len(f.msgs) != 1 {
+				t.Fatalf("should have failed once, got %q", f.msgs)
+			}
+			t.Log(f.msgs[0])
+			for _, expected := range tc.expected {
+				if !strings.Contains(f.msgs[0], expected) {
+					t.Errorf("should contain %q", expected)
+				}
+			}
+		})
+	}
+
+	t.Run("lenient", func(t *testing.T) {
+		actual := must.FromJSON[fixture](t, []byte(`{"name": "a", "cuont": 1, "name": "b"}`))
+		if actual.Name != "b" {
+			t.Errorf("unexpected value: %#v", actual)
+		}
+
+		f := &fatals{}
+		must.FromJSON[fixture](f, []byte(`{"name": "a"} trailing`))
+		if len(f.msgs) != 1 || !strings.Contains(f.msgs[0], "after top-level value") {
+			t.Errorf("should always reject trailing data, got %q", f.msgs)
+		}
+	})
+
+	t.Run("strict", func(t *testing.T) {
+		actual := must.FromJSON[fixture](t, []byte(`{"name": "a", "nested": {"id": "1"}}`), must.StrictJSON())
+		if actual.Nested == nil || actual.Nested.ID != "1" {
+			t.Errorf("unexpected value: %#v", actual)
+		}
+	})
+
+	t.Run("use number", func(t *testing.T) {
+		actual := must.FromJSON[map[string]any](t, []byte(`{"n": 12345678901234567890}`), must.UseNumber())
+		if n, ok := actual["n"].(json.Number); !ok || n.String() != "12345678901234567890" {
+			t.Errorf("unexpected value: %#v", actual)
+		}
+	})
+}
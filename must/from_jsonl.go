@@ -0,0 +1,106 @@
+package must
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/jahkeup/testthings"
+)
+
+// FromJSONL will parse the given JSON Lines text, one value of type T per
+// line. Blank lines are skipped. If a line does not parse, the test will be
+// failed. Alternatively, this function may be used in initialization contexts
+// by passing nil for the testingT object in which case errors will cause the
+// program to panic at runtime.
+func FromJSONL[T any](testingT testthings.Terminator, text []byte, opts ...DecodeOption) []T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	return Must[[]T](testingT, func() []T {
+		return decodeText[[]T](testingT, "", jsonLinesFormat, text, opts...)
+	})
+}
+
+// EachJSONL streams the JSON Lines from the reader, calling fn with each value
+// as it's parsed until fn returns false. Blank lines are skipped. If a line
+// does not parse, or the reader errors, the test will be failed.
+// Alternatively, nil may be passed for testingT in which case errors will
+// cause the program to panic at runtime.
+func EachJSONL[T any](testingT testthings.Terminator, r io.Reader, fn func(T) bool, opts ...DecodeOption) {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	elemType := reflect.TypeOf((*T)(nil)).Elem()
+	err := scanJSONLines(r, elemType, func(elem reflect.Value) bool {
+		// asserting a nil interface value to T panics, so go through *T.
+		return fn(*elem.Addr().Interface().(*T))
+	}, opts...)
+	if err != nil {
+		fail(testingT, fmt.Sprintf("cannot unmarshal %s into %v: %v", jsonLinesFormat.name, elemType, err))
+	}
+}
+
+// decodeJSONLines decodes each line of the JSON Lines text as an element of
+// out (a pointer to a slice). Blank lines are skipped.
+func decodeJSONLines(text []byte, out any, opts ...DecodeOption) error {
+	slice := reflect.ValueOf(out)
+	if slice.Kind() != reflect.Pointer || slice.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("json lines decode into %T, need a pointer to a slice", out)
+	}
+	slice = slice.Elem()
+	slice.SetLen(0)
+
+	return scanJSONLines(bytes.NewReader(text), slice.Type().Elem(), func(elem reflect.Value) bool {
+		slice.Set(reflect.Append(slice, elem))
+		return true
+	}, opts...)
+}
+
+// scanJSONLines decodes each line from the reader as a value of the type,
+// calling fn with each until it returns false.
+func scanJSONLines(r io.Reader, elemType reflect.Type, fn func(reflect.Value) bool, opts ...DecodeOption) error {
+	br := bufio.NewReader(r)
+	for lineNum := 1; ; lineNum++ {
+		line, readErr := br.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return fmt.Errorf("line %d: %w", lineNum, readErr)
+		}
+
+		if len(bytes.TrimSpace(line)) > 0 {
+			elem := reflect.New(elemType)
+			if err := decodeJSON(line, elem.Interface(), opts...); err != nil {
+				return locateJSONLine(line, lineNum, err)
+			}
+			if !fn(elem.Elem()) {
+				return nil
+			}
+		}
+
+		if readErr == io.EOF {
+			return nil
+		}
+	}
+}
+
+// locateJSONLine locates the error for the line within the whole text.
+func locateJSONLine(line []byte, lineNum int, err error) error {
+	var textErr *jsonTextError
+	if !errors.As(err, &textErr) {
+		return fmt.Errorf("line %d: %w", lineNum, err)
+	}
+
+	located := *textErr
+	located.Line = lineNum
+	located.snippet = formatSnippet(string(bytes.TrimRight(line, "\r\n")), lineNum, textErr.Column)
+	return &located
+}
@@ -0,0 +1,145 @@
+package must_test
+
+import (
+	"bytes"
+	"encoding/gob"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jahkeup/testthings/must"
+)
+
+func TestFromXML(t *testing.T) {
+	actual := must.FromXML[people](t, []byte(`<people><person><name>Ada</name><age>36</age></person></people>`))
+	if !reflect.DeepEqual(actual.Person, expectedPeople[:1]) {
+		t.Errorf("unexpected people: %#v", actual)
+	}
+
+	must.Panic(t, func() {
+		must.FromXML[people](nil, []byte(`<people>`))
+	}, must.PanicMessage("cannot unmarshal xml"))
+}
+
+func TestFromYAML(t *testing.T) {
+	actual := must.FromYAML[[]person](t, []byte("- name: Ada\n  age: 36\n"))
+	if !reflect.DeepEqual(actual, expectedPeople[:1]) {
+		t.Errorf("unexpected people: %#v", actual)
+	}
+
+	f := &fatals{}
+	must.FromYAML[person](f, []byte("name: Ada\nagee: 36\n"), must.DisallowUnknownFields())
+	if len(f.msgs) != 1 || !strings.Contains(f.msgs[0], "agee") {
+		t.Errorf("should fail on unknown fields, got %q", f.msgs)
+	}
+}
+
+func TestFromGob(t *testing.T) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(expectedPeople); err != nil {
+		t.Fatal(err)
+	}
+
+	actual := must.FromGob[[]person](t, buf.Bytes())
+	if !reflect.DeepEqual(actual, expectedPeople) {
+		t.Errorf("unexpected people: %#v", actual)
+	}
+}
+
+type csvCase struct {
+	Name    string        `csv:"name"`
+	Timeout time.Duration `csv:"timeout"`
+	At      time.Time     `csv:"at"`
+	Retries *uint8        `csv:"retries"`
+	Enabled bool
+	Ignored string `csv:"-"`
+}
+
+func TestFromCSV(t *testing.T) {
+	text := []byte(strings.Join([]string{
+		"name,timeout,at,retries,enabled,comment",
+		"first,1s,2023-01-02T03:04:05Z,3,true,ignored column",
+		"second,,,,false,",
+	}, "\n"))
+
+	actual := must.FromCSV[csvCase](t, text)
+	if len(actual) != 2 {
+		t.Fatalf("unexpected cases: %#v", actual)
+	}
+
+	first := actual[0]
+	if first.Name != "first" || first.Timeout != time.Second || !first.Enabled ||
+		first.At.Year() != 2023 || first.Retries == nil || *first.Retries != 3 {
+		t.Errorf("unexpected first case: %#v", first)
+	}
+	if second := actual[1]; second.Retries != nil || !second.At.IsZero() {
+		t.Errorf("unexpected second case: %#v", second)
+	}
+
+	records := must.FromCSV[[]string](t, text)
+	if len(records) != 3 || records[0][0] != "name" {
+		t.Errorf("unexpected records: %q", records)
+	}
+
+	f := &fatals{}
+	must.FromCSV[csvCase](f, []byte("name,retries\nfirst,300\n"))
+	if len(f.msgs) != 1 || !strings.Contains(f.msgs[0], `("retries")`) {
+		t.Errorf("should fail out of range, got %q", f.msgs)
+	}
+}
+
+func TestFromJSONL(t *testing.T) {
+	text := []byte("{\"name\": \"Ada\", \"age\": 36}\n\n{\"name\": \"Grace\", \"age\": 85}\n")
+
+	actual := must.FromJSONL[person](t, text)
+	if !reflect.DeepEqual(actual, expectedPeople) {
+		t.Errorf("unexpected people: %#v", actual)
+	}
+
+	t.Run("each", func(t *testing.T) {
+		var names []string
+		must.EachJSONL(t, bytes.NewReader(text), func(p person) bool {
+			names = append(names, p.Name)
+			return false
+		})
+		if !reflect.DeepEqual(names, []string{"Ada"}) {
+			t.Errorf("should stop after the first, got %q", names)
+		}
+	})
+
+	t.Run("null", func(t *testing.T) {
+		var values []any
+		must.EachJSONL(t, strings.NewReader("1\nnull\n"), func(v any) bool {
+			values = append(values, v)
+			return true
+		})
+		if !reflect.DeepEqual(values, []any{1.0, nil}) {
+			t.Errorf("unexpected values: %#v", values)
+		}
+	})
+
+	t.Run("failure", func(t *testing.T) {
+		f := &fatals{}
+		var count int
+		must.EachJSONL(f, strings.NewReader("{\"name\": \"Ada\"}\n{\"name\": \"Grace\", \"cuont\": 1}\n"), func(p person) bool {
+			count++
+			return true
+		}, must.DisallowUnknownFields())
+		if count != 1 {
+			t.Errorf("should stream values before the failure, got %d", count)
+		}
+		if len(f.msgs) != 1 || !strings.Contains(f.msgs[0], "line 2, column 19") {
+			t.Errorf("should fail at the line, got %q", f.msgs)
+		}
+		t.Log(f.msgs)
+	})
+
+	t.Run("trailing data", func(t *testing.T) {
+		f := &fatals{}
+		must.FromJSONL[person](f, []byte("{\"name\": \"Ada\"}\n{\"name\": \"Grace\"} {\"name\": \"Hopper\"}\n"))
+		if len(f.msgs) != 1 || !strings.Contains(f.msgs[0], "line 2, column 19") {
+			t.Errorf("should reject the line's trailing data, got %q", f.msgs)
+		}
+	})
+}
@@ -0,0 +1,29 @@
+package must
+
+import (
+	"bytes"
+	"encoding/xml"
+
+	"github.com/jahkeup/testthings"
+)
+
+// FromXML will parse the given xml text into an output object. If the object
+// does not parse, the test will be failed. Alternatively, this function may be
+// used in initialization contexts by passing nil for the testingT object in
+// which case errors will cause the program to panic at runtime.
+func FromXML[T any](testingT testthings.Terminator, text []byte) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	return Must[T](testingT, func() T {
+		return decodeText[T](testingT, "", xmlFormat, text)
+	})
+}
+
+// decodeXML decodes the XML text into out (a pointer).
+func decodeXML(text []byte, out any, _ ...DecodeOption) error {
+	return xml.NewDecoder(bytes.NewReader(text)).Decode(out)
+}
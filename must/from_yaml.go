@@ -0,0 +1,40 @@
+package must
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/jahkeup/testthings"
+)
+
+// FromYAML will parse the given yaml text into an output object. If the object
+// does not parse, the test will be failed. Alternatively, this function may be
+// used in initialization contexts by passing nil for the testingT object in
+// which case errors will cause the program to panic at runtime.
+//
+// Of the options, only DisallowUnknownFields applies to YAML.
+func FromYAML[T any](testingT testthings.Terminator, text []byte, opts ...DecodeOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	return Must[T](testingT, func() T {
+		return decodeText[T](testingT, "", yamlFormat, text, opts...)
+	})
+}
+
+// decodeYAML decodes the YAML text into out (a pointer). Unknown fields are
+// rejected when the DisallowUnknownFields option is given.
+func decodeYAML(text []byte, out any, opts ...DecodeOption) error {
+	var options decodeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	dec := yaml.NewDecoder(bytes.NewReader(text))
+	dec.KnownFields(options.disallowUnknownFields)
+	return dec.Decode(out)
+}
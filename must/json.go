@@ -0,0 +1,423 @@
+package must
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// jsonSnippetWidth limits the amount of the offending line that's included in
+// errors.
+const jsonSnippetWidth = 72
+
+// jsonTextError is an error located at an offset within some JSON text.
+type jsonTextError struct {
+	Err    error
+	Offset int64
+	Line   int
+	Column int
+
+	snippet string
+}
+
+// newJSONTextError locates the offset in the text.
+func newJSONTextError(text []byte, offset int64, err error) *jsonTextError {
+	if offset < 0 {
+		offset = 0
+	}
+	if offset > int64(len(text)) {
+		offset = int64(len(text))
+	}
+
+	before := text[:offset]
+	lineStart := bytes.LastIndexByte(before, '\n') + 1
+	lineEnd := bytes.IndexByte(text[lineStart:], '\n')
+	if lineEnd < 0 {
+		lineEnd = len(text)
+	} else {
+		lineEnd += lineStart
+	}
+
+	line := bytes.Count(before, []byte("\n")) + 1
+	column := utf8.RuneCount(text[lineStart:offset]) + 1
+
+	return &jsonTextError{
+		Err:     err,
+		Offset:  offset,
+		Line:    line,
+		Column:  column,
+		snippet: formatSnippet(string(bytes.TrimRight(text[lineStart:lineEnd], "\r")), line, column),
+	}
+}
+
+// Error implements error.
+func (e *jsonTextError) Error() string {
+	return fmt.Sprintf("line %d, column %d: %v\n%s", e.Line, e.Column, e.Err, e.snippet)
+}
+
+// Unwrap returns the underlying error.
+func (e *jsonTextError) Unwrap() error {
+	return e.Err
+}
+
+// formatSnippet formats the line with a marker under the column, trimming long
+// lines around the column.
+func formatSnippet(text string, line, column int) string {
+	runes := []rune(strings.ReplaceAll(text, "\t", " "))
+	start, end := 0, len(runes)
+	if end > jsonSnippetWidth {
+		start = column - 1 - jsonSnippetWidth/2
+		if start < 0 {
+			start = 0
+		}
+		end = start + jsonSnippetWidth
+		if end > len(runes) {
+			end = len(runes)
+			start = end - jsonSnippetWidth
+		}
+	}
+
+	prefix := fmt.Sprintf("%5d | ", line)
+	marker := strings.Repeat(" ", len(prefix)-2) + "| " + strings.Repeat(" ", column-1-start) + "^"
+	return prefix + string(runes[start:end]) + "\n" + marker
+}
+
+// decodeJSON decodes the text into out (a pointer), reporting errors at their
+// location in the text.
+func decodeJSON(text []byte, out any, opts ...DecodeOption) error {
+	var options decodeOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(text))
+	if options.disallowUnknownFields {
+		dec.DisallowUnknownFields()
+	}
+	if options.useNumber {
+		dec.UseNumber()
+	}
+
+	if err := dec.Decode(out); err != nil {
+		return locateJSONError(text, dec.InputOffset(), err)
+	}
+
+	// like json.Unmarshal, only whitespace may follow the value.
+	offset := dec.InputOffset()
+	if _, err := dec.Token(); err != io.EOF {
+		offset = skipJSONSpace(text, offset)
+		return newJSONTextError(text, offset, errors.New("unexpected data after top-level value"))
+	}
+
+	if options.rejectDuplicateKeys {
+		if err := checkDuplicateKeys(text); err != nil {
+			return err
+		}
+	}
+
+	if options.requireFields {
+		var doc any
+		docDec := json.NewDecoder(bytes.NewReader(text))
+		docDec.UseNumber()
+		if err := docDec.Decode(&doc); err != nil {
+			return locateJSONError(text, docDec.InputOffset(), err)
+		}
+		if err := checkRequiredFields(reflect.TypeOf(out), doc, ""); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+var unknownFieldPattern = regexp.MustCompile(`^json: unknown field "(.*)"$`)
+
+// locateJSONError finds the location of the error in the text, the decoder's
+// offset is used when the error doesn't carry one of its own.
+func locateJSONError(text []byte, decoderOffset int64, err error) error {
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+	)
+	switch {
+	case errors.As(err, &syntaxErr):
+		// the offset is just past the offending byte
+		return newJSONTextError(text, syntaxErr.Offset-1, err)
+	case errors.As(err, &typeErr):
+		// the offset is just past the offending value
+		return newJSONTextError(text, findValueStart(text, typeErr.Offset), err)
+	case err == io.EOF:
+		return newJSONTextError(text, int64(len(text)), errors.New("unexpected end of JSON input"))
+	case err == io.ErrUnexpectedEOF:
+		return newJSONTextError(text, int64(len(text)), err)
+	}
+
+	if m := unknownFieldPattern.FindStringSubmatch(err.Error()); m != nil {
+		if offset, ok := findKey(text, m[1]); ok {
+			return newJSONTextError(text, offset, err)
+		}
+	}
+
+	return newJSONTextError(text, decoderOffset, err)
+}
+
+// skipJSONSpace returns the offset of the next non-whitespace byte.
+func skipJSONSpace(text []byte, offset int64) int64 {
+	for offset < int64(len(text)) {
+		switch text[offset] {
+		case ' ', '\t', '\r', '\n':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// findValueStart finds the start of the value that ends at offset.
+func findValueStart(text []byte, end int64) int64 {
+	dec := json.NewDecoder(bytes.NewReader(text))
+	var start int64
+	for {
+		before := dec.InputOffset()
+		if _, err := dec.Token(); err != nil {
+			return end
+		}
+		if dec.InputOffset() >= end {
+			start = skipJSONDelims(text, before)
+			break
+		}
+	}
+	return start
+}
+
+// skipJSONDelims skips whitespace and the separators between tokens.
+func skipJSONDelims(text []byte, offset int64) int64 {
+	for offset < int64(len(text)) {
+		switch text[offset] {
+		case ' ', '\t', '\r', '\n', ',', ':':
+			offset++
+		default:
+			return offset
+		}
+	}
+	return offset
+}
+
+// findKey returns the offset of the first object key with the name.
+func findKey(text []byte, name string) (int64, bool) {
+	var found int64 = -1
+	_ = walkJSON(text, func(path []string, key string, keyOffset int64) error {
+		if key == name {
+			found = keyOffset
+			return errStopWalk
+		}
+		return nil
+	})
+	return found, found >= 0
+}
+
+var errStopWalk = errors.New("stop walk")
+
+// walkJSON walks the first value in the text, calling fn for each object key
+// with the path to its object and the key's offset.
+func walkJSON(text []byte, fn func(path []string, key string, keyOffset int64) error) error {
+	dec := json.NewDecoder(bytes.NewReader(text))
+	dec.UseNumber()
+
+	var walk func(path []string) error
+	walk = func(path []string) error {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+
+		switch tok {
+		case json.Delim('{'):
+			for dec.More() {
+				keyOffset := skipJSONDelims(text, dec.InputOffset())
+				keyTok, err := dec.Token()
+				if err != nil {
+					return err
+				}
+				key := keyTok.(string)
+				if err := fn(path, key, keyOffset); err != nil {
+					return err
+				}
+				if err := walk(append(path[:len(path):len(path)], key)); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+			return err
+
+		case json.Delim('['):
+			for i := 0; dec.More(); i++ {
+				if err := walk(append(path[:len(path):len(path)], strconv.Itoa(i))); err != nil {
+					return err
+				}
+			}
+			_, err = dec.Token()
+			return err
+		}
+
+		return nil
+	}
+
+	err := walk(nil)
+	if err == errStopWalk {
+		return nil
+	}
+	return err
+}
+
+// checkDuplicateKeys returns an error for the first object key that's repeated.
+func checkDuplicateKeys(text []byte) error {
+	seen := map[string]map[string]bool{}
+	var dupErr error
+	_ = walkJSON(text, func(path []string, key string, keyOffset int64) error {
+		// objects are identified by their path, which includes the index of
+		// array elements.
+		obj := jsonPointer(path)
+		if seen[obj] == nil {
+			seen[obj] = map[string]bool{}
+		}
+		if seen[obj][key] {
+			dupErr = newJSONTextError(text, keyOffset, fmt.Errorf("duplicate key %q in object at %q", key, obj))
+			return errStopWalk
+		}
+		seen[obj][key] = true
+		return nil
+	})
+	return dupErr
+}
+
+// jsonPointer formats the path as an RFC 6901 JSON Pointer.
+func jsonPointer(path []string) string {
+	var sb strings.Builder
+	for _, p := range path {
+		sb.WriteString("/")
+		sb.WriteString(strings.NewReplacer("~", "~0", "/", "~1").Replace(p))
+	}
+	return sb.String()
+}
+
+// checkRequiredFields checks that the decoded document has each of the fields
+// tagged `must:"required"` in the type it was decoded into.
+func checkRequiredFields(t reflect.Type, doc any, path string) error {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if doc == nil {
+		return nil
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			return nil
+		}
+		return checkRequiredStructFields(t, obj, path)
+
+	case reflect.Slice, reflect.Array:
+		arr, ok := doc.([]any)
+		if !ok {
+			return nil
+		}
+		for i, elem := range arr {
+			if err := checkRequiredFields(t.Elem(), elem, path+"/"+strconv.Itoa(i)); err != nil {
+				return err
+			}
+		}
+
+	case reflect.Map:
+		obj, ok := doc.(map[string]any)
+		if !ok {
+			return nil
+		}
+		for key, elem := range obj {
+			if err := checkRequiredFields(t.Elem(), elem, path+jsonPointer([]string{key})); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+func checkRequiredStructFields(t reflect.Type, obj map[string]any, path string) error {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, skip := jsonFieldName(field)
+		if skip {
+			continue
+		}
+
+		// embedded structs' fields are part of the same object
+		if field.Anonymous && name == "" {
+			ft := field.Type
+			for ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				if err := checkRequiredStructFields(ft, obj, path); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		value, present := lookupJSONKey(obj, name)
+		if !present {
+			if field.Tag.Get("must") == "required" {
+				return fmt.Errorf("missing required field %q (%s.%s) in object at %q", name, t, field.Name, path)
+			}
+			continue
+		}
+
+		if err := checkRequiredFields(field.Type, value, path+jsonPointer([]string{name})); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// jsonFieldName returns the name given to the field by its json tag, if any,
+// and whether the field is skipped by encoding/json.
+func jsonFieldName(field reflect.StructField) (name string, skip bool) {
+	if !field.IsExported() && !field.Anonymous {
+		return "", true
+	}
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ = strings.Cut(tag, ",")
+	return name, false
+}
+
+// lookupJSONKey finds the key like encoding/json does: preferring an exact
+// match, otherwise matching case-insensitively.
+func lookupJSONKey(obj map[string]any, name string) (any, bool) {
+	if v, ok := obj[name]; ok {
+		return v, true
+	}
+	for k, v := range obj {
+		if strings.EqualFold(k, name) {
+			return v, true
+		}
+	}
+	return nil, false
+}
@@ -0,0 +1,248 @@
+package must
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jahkeup/testthings"
+)
+
+// JSONCompareOption configures how JSON documents are compared.
+type JSONCompareOption func(*jsonCompareOptions)
+
+type jsonCompareOptions struct {
+	ignorePaths  [][]string
+	ignoreFields map[string]bool
+}
+
+// IgnorePaths ignores the values at the RFC 6901 JSON Pointers, like
+// "/meta/timestamp", when comparing documents. A "*" segment matches any key
+// or array index, like "/items/*/id".
+func IgnorePaths(pointers ...string) JSONCompareOption {
+	return func(o *jsonCompareOptions) {
+		for _, p := range pointers {
+			o.ignorePaths = append(o.ignorePaths, parseJSONPointer(p))
+		}
+	}
+}
+
+// IgnoreFields ignores the Go struct fields, by their dotted path from the
+// compared value like "Meta.CreatedAt", in JSONRoundTrip's comparison of the
+// values. Use IgnorePaths for the JSON documents.
+func IgnoreFields(fields ...string) JSONCompareOption {
+	return func(o *jsonCompareOptions) {
+		if o.ignoreFields == nil {
+			o.ignoreFields = map[string]bool{}
+		}
+		for _, f := range fields {
+			o.ignoreFields[f] = true
+		}
+	}
+}
+
+func (o jsonCompareOptions) ignored(path []string) bool {
+	for _, ignore := range o.ignorePaths {
+		if len(ignore) != len(path) {
+			continue
+		}
+		matched := true
+		for i := range ignore {
+			if ignore[i] != "*" && ignore[i] != path[i] {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
+
+// JSONEq compares the JSON documents, failing the test with each of the paths
+// that differ. The documents are compared canonically: whitespace, object key
+// order, and the formatting of numbers don't matter. Alternatively, nil may be
+// passed for testingT in which case differences will cause the program to
+// panic at runtime.
+func JSONEq(testingT testthings.Terminator, expected, actual []byte, opts ...JSONCompareOption) {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	var options jsonCompareOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	expectedDoc, err := canonicalJSON(expected)
+	if err != nil {
+		fail(testingT, fmt.Sprintf("json eq! but: expected is not json: %v", err))
+		return
+	}
+	actualDoc, err := canonicalJSON(actual)
+	if err != nil {
+		fail(testingT, fmt.Sprintf("json eq! but: actual is not json: %v", err))
+		return
+	}
+
+	if diffs := diffJSON(nil, expectedDoc, actualDoc, options); len(diffs) > 0 {
+		fail(testingT, fmt.Sprintf("json eq! but:\n%s", formatDiffs(diffs)))
+	}
+}
+
+// canonicalJSON decodes the text into a document that can be compared.
+func canonicalJSON(text []byte) (any, error) {
+	var doc any
+	if err := decodeJSON(text, &doc, UseNumber()); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+// jsonDiff is a difference at a path in a JSON document.
+type jsonDiff struct {
+	Path     string
+	Expected any
+	Actual   any
+
+	missing    bool
+	unexpected bool
+}
+
+func (d jsonDiff) String() string {
+	path := d.Path
+	if path == "" {
+		path = "(document)"
+	}
+
+	switch {
+	case d.missing:
+		return fmt.Sprintf("%s: missing, expected %s", path, formatJSONValue(d.Expected))
+	case d.unexpected:
+		return fmt.Sprintf("%s: unexpected %s", path, formatJSONValue(d.Actual))
+	default:
+		return fmt.Sprintf("%s: expected %s, got %s", path, formatJSONValue(d.Expected), formatJSONValue(d.Actual))
+	}
+}
+
+func formatDiffs[D fmt.Stringer](diffs []D) string {
+	lines := make([]string, 0, len(diffs))
+	for _, d := range diffs {
+		lines = append(lines, "\t"+d.String())
+	}
+	return strings.Join(lines, "\n")
+}
+
+func formatJSONValue(v any) string {
+	text, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprintf("%v", v)
+	}
+	return string(text)
+}
+
+// diffJSON compares the documents, returning the differences in path order.
+func diffJSON(path []string, expected, actual any, options jsonCompareOptions) []jsonDiff {
+	if options.ignored(path) {
+		return nil
+	}
+
+	child := func(key string) []string {
+		return append(path[:len(path):len(path)], key)
+	}
+
+	switch e := expected.(type) {
+	case map[string]any:
+		a, ok := actual.(map[string]any)
+		if !ok {
+			break
+		}
+		keys := make([]string, 0, len(e)+len(a))
+		for k := range e {
+			keys = append(keys, k)
+		}
+		for k := range a {
+			if _, ok := e[k]; !ok {
+				keys = append(keys, k)
+			}
+		}
+		sort.Strings(keys)
+
+		var diffs []jsonDiff
+		for _, k := range keys {
+			ev, inExpected := e[k]
+			av, inActual := a[k]
+			switch {
+			case options.ignored(child(k)):
+			case !inActual:
+				diffs = append(diffs, jsonDiff{Path: jsonPointer(child(k)), Expected: ev, missing: true})
+			case !inExpected:
+				diffs = append(diffs, jsonDiff{Path: jsonPointer(child(k)), Actual: av, unexpected: true})
+			default:
+				diffs = append(diffs, diffJSON(child(k), ev, av, options)...)
+			}
+		}
+		return diffs
+
+	case []any:
+		a, ok := actual.([]any)
+		if !ok {
+			break
+		}
+		var diffs []jsonDiff
+		for i := 0; i < len(e) || i < len(a); i++ {
+			k := strconv.Itoa(i)
+			switch {
+			case options.ignored(child(k)):
+			case i >= len(a):
+				diffs = append(diffs, jsonDiff{Path: jsonPointer(child(k)), Expected: e[i], missing: true})
+			case i >= len(e):
+				diffs = append(diffs, jsonDiff{Path: jsonPointer(child(k)), Actual: a[i], unexpected: true})
+			default:
+				diffs = append(diffs, diffJSON(child(k), e[i], a[i], options)...)
+			}
+		}
+		return diffs
+
+	case json.Number:
+		if a, ok := actual.(json.Number); ok && numbersEqual(e, a) {
+			return nil
+		}
+
+	default:
+		if expected == actual {
+			return nil
+		}
+	}
+
+	return []jsonDiff{{Path: jsonPointer(path), Expected: expected, Actual: actual}}
+}
+
+// numbersEqual compares the numbers by value.
+func numbersEqual(a, b json.Number) bool {
+	if a == b {
+		return true
+	}
+	ar, aok := new(big.Rat).SetString(a.String())
+	br, bok := new(big.Rat).SetString(b.String())
+	return aok && bok && ar.Cmp(br) == 0
+}
+
+// parseJSONPointer splits the RFC 6901 JSON Pointer into its unescaped
+// segments.
+func parseJSONPointer(pointer string) []string {
+	if pointer == "" {
+		return nil
+	}
+	segments := strings.Split(strings.TrimPrefix(pointer, "/"), "/")
+	for i, s := range segments {
+		segments[i] = strings.NewReplacer("~1", "/", "~0", "~").Replace(s)
+	}
+	return segments
+}
@@ -0,0 +1,56 @@
+package must_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/jahkeup/testthings/must"
+)
+
+func TestJSONEq(t *testing.T) {
+	t.Run("canonical", func(t *testing.T) {
+		must.JSONEq(t,
+			[]byte(`{"a": 1, "b": [true, null, {"c": "d"}], "e": 1.5}`),
+			[]byte(`{"e":15e-1,"b":[true,null,{"c":"d"}],"a":1.0}`),
+		)
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		must.JSONEq(t,
+			[]byte(`{"id": 1, "items": [{"id": "a", "at": 1}, {"id": "b", "at": 2}]}`),
+			[]byte(`{"id": 2, "items": [{"id": "a", "at": 3}, {"id": "b", "at": 4}]}`),
+			must.IgnorePaths("/id", "/items/*/at"),
+		)
+	})
+
+	t.Run("diff", func(t *testing.T) {
+		f := &fatals{}
+		must.JSONEq(f,
+			[]byte(`{"a": 1, "b": [1, 2, 3], "c": {"d/e": "f"}, "removed": true}`),
+			[]byte(`{"a": "1", "b": [1, 2], "c": {"d/e": "g"}, "added": null}`),
+		)
+		if len(f.msgs) != 1 {
+			t.Fatalf("should have failed once, got %q", f.msgs)
+		}
+		t.Log(f.msgs[0])
+		for _, expected := range []string{
+			`/a: expected 1, got "1"`,
+			`/added: unexpected null`,
+			`/b/2: missing, expected 3`,
+			`/c/d~1e: expected "f", got "g"`,
+			`/removed: missing, expected true`,
+		} {
+			if !strings.Contains(f.msgs[0], expected) {
+				t.Errorf("should contain %q", expected)
+			}
+		}
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		f := &fatals{}
+		must.JSONEq(f, []byte(`{}`), []byte(`{`))
+		if len(f.msgs) != 1 || !strings.Contains(f.msgs[0], "actual is not json") {
+			t.Fatalf("should have failed, got %q", f.msgs)
+		}
+	})
+}
@@ -1,16 +1,27 @@
 package must
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"time"
 
 	"github.com/jahkeup/testthings"
 	"github.com/jahkeup/testthings/testerr"
 )
 
 type Mustable[T any] interface {
-	~func(*T) | ~func() T | ~func() *T | ~func() (T, error) | ~func() (*T, error)
+	~func(*T) | ~func() T | ~func() *T | ~func() (T, error) | ~func() (*T, error) |
+		~func(context.Context) T | ~func(context.Context) (T, error) | ~func(context.Context) (*T, error)
 }
 
+// Must calls the mustable function and returns its value, failing the test if
+// it errors or panics. Context-taking functions are given a context that's
+// cancelled with the test. Values constructed as a *T are copied to return a
+// T, types that must not be copied are refused (see MustPtr). Alternatively,
+// nil may be passed for testingT in which case errors will cause the program to
+// panic at runtime.
 func Must[T any, F Mustable[T]](testingT testthings.Terminator, mustable F) T {
 	if th, ok := testingT.(interface {
 		Helper()
@@ -18,56 +29,72 @@ func Must[T any, F Mustable[T]](testingT testthings.Terminator, mustable F) T {
 		th.Helper()
 	}
 
-	// ret is the final value to be returned. Declared here to allow the panic
-	// recovery to clear the value.
-	var ret T
+	return mustWithin[T](testingT, nil, 0, mustable)
+}
+
+// MustTimeout is Must with a timeout for context-taking functions: the context
+// passed to them is cancelled when the timeout elapses.
+//
+// Contexts given to functions are otherwise cancelled at the end of the test,
+// when testingT supports Cleanup, or when the function returns.
+func MustTimeout[T any, F Mustable[T]](testingT testthings.Terminator, timeout time.Duration, mustable F) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	return mustWithin[T](testingT, nil, timeout, mustable)
+}
+
+// mustWithin calls the mustable function, giving context-taking functions a
+// context from ctx (when not nil) or the test, limited by the timeout.
+func mustWithin[T any, F Mustable[T]](testingT testthings.Terminator, ctx context.Context, timeout time.Duration, mustable F) (ret T) {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
 
 	// When not hooking into testingT, set up to recover any panics that might
-	// propagate from the inner function.
+	// propagate from the inner function. The return value is named to allow
+	// the panic recovery to clear the value.
 	if testingT != nil {
-		recoverAsFatal := func() {
-			if r := recover(); r != nil {
-				testingT.Fatal(fmt.Sprintf("must! but:\n%v", r))
-				// when recovering, ensure the type's zero value is returned
-				var zero T
-				ret = zero
-			}
-		}
-		defer recoverAsFatal()
+		// when recovering, ensure the type's zero value is returned
+		defer recoverAsFatal(testingT, func() {
+			var zero T
+			ret = zero
+		})
 	}
 
 	doFail := func(v any) {
-		msg := fmt.Sprintf("must! but: %v", v)
-		if testingT != nil {
-			testingT.Fatal(msg)
-		} else {
-			panic(msg)
+		if th, ok := testingT.(interface {
+			Helper()
+		}); ok {
+			th.Helper()
 		}
+		fail(testingT, fmt.Sprintf("must! but: %v", v))
 	}
 
 	// Unfortunately Go's generics don't eliminate the need to reflect on the
 	// signatures, so we do that and invoke the right form. Nonetheless, its
 	// nice to see call sites cleaned up by this :)
 	switch fn := any(mustable).(type) {
-	case func(*T): // injected argument to mutate and return
-		// inject is used as dedicated working-memory space, to control the
-		// indirection of the object given to callers.
-		var inject *T
-		inject = new(T)
-		fn(inject)
-		ret = *inject
+	case func(*T), func() *T, func() (*T, error), func(context.Context) (*T, error):
+		// The constructed *T is copied to return a T, so that must be safe to
+		// do for the type.
+		if err := copyUnsafe(reflect.TypeOf(&ret).Elem()); err != nil {
+			doFail(err)
+			return ret
+		}
+		if retptr := constructPtr[T](testingT, ctx, timeout, fn); retptr != nil {
+			ret = *retptr
+		}
 		return ret
 
 	case func() T: // constructor-like without errors (can panic)
 		ret = fn()
 		return ret
-	case func() *T: // constructor-like without errors (can panic)
-		retptr := fn()
-		if retptr == nil {
-			doFail(testerr.NilPointer)
-		}
-		return ret
-
 	case func() (T, error): // constructor-like with error
 		var err error
 		ret, err = fn()
@@ -75,15 +102,193 @@ func Must[T any, F Mustable[T]](testingT testthings.Terminator, mustable F) T {
 			doFail(err)
 		}
 		return ret
+
+	case func(context.Context) T: // constructor-like with context
+		ctx, release := mustContext(testingT, ctx, timeout)
+		defer release()
+		ret = fn(ctx)
+		return ret
+	case func(context.Context) (T, error): // constructor-like with context and error
+		ctx, release := mustContext(testingT, ctx, timeout)
+		defer release()
+		var err error
+		ret, err = fn(ctx)
+		if err != nil {
+			doFail(contextFailure(ctx, timeout, err))
+		}
+		return ret
+
+	default:
+		panic("uhhhhhh, nice. make an issue via GitHub please :)")
+	}
+}
+
+// constructPtr calls the pointer-constructing function, failing when it errors
+// or constructs nil. The returned pointer is nil when construction failed.
+func constructPtr[T any](testingT testthings.Terminator, ctx context.Context, timeout time.Duration, fn any) *T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	doFail := func(v any) {
+		if th, ok := testingT.(interface {
+			Helper()
+		}); ok {
+			th.Helper()
+		}
+		fail(testingT, fmt.Sprintf("must! but: %v", v))
+	}
+
+	switch fn := fn.(type) {
+	case func(*T): // injected argument to mutate and return
+		// inject is used as dedicated working-memory space, to control the
+		// indirection of the object given to callers.
+		inject := new(T)
+		fn(inject)
+		return inject
+
+	case func() *T: // constructor-like without errors (can panic)
+		retptr := fn()
+		if retptr == nil {
+			doFail(testerr.NilPointer)
+		}
+		return retptr
+
 	case func() (*T, error): // constructor-like with error
 		retptr, err := fn()
 		if err != nil {
 			doFail(err)
+			return nil
 		}
-		ret = *retptr
-		return ret
+		if retptr == nil {
+			doFail(testerr.NilPointer)
+		}
+		return retptr
+
+	case func(context.Context) (*T, error): // constructor-like with context and error
+		ctx, release := mustContext(testingT, ctx, timeout)
+		defer release()
+		retptr, err := fn(ctx)
+		if err != nil {
+			doFail(contextFailure(ctx, timeout, err))
+			return nil
+		}
+		if retptr == nil {
+			doFail(testerr.NilPointer)
+		}
+		return retptr
 
 	default:
 		panic("uhhhhhh, nice. make an issue via GitHub please :)")
 	}
 }
+
+// mustContext creates the context given to context-taking functions, from the
+// parent when given. When testingT supports Cleanup, the context is cancelled
+// with the test and release is a no-op; otherwise release cancels the context.
+func mustContext(testingT testthings.Terminator, parent context.Context, timeout time.Duration) (ctx context.Context, release func()) {
+	cleanuper, scoped := testingT.(testthings.Cleanuper)
+
+	ctx = parent
+	switch {
+	case ctx != nil:
+	case scoped:
+		ctx = testthings.C(cleanuper)
+	default:
+		ctx = context.Background()
+	}
+
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	if scoped {
+		cleanuper.Cleanup(cancel)
+		return ctx, func() {}
+	}
+	return ctx, cancel
+}
+
+// contextFailure describes the error, noting when the context was cancelled or
+// exceeded its deadline.
+func contextFailure(ctx context.Context, timeout time.Duration, err error) string {
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		return fmt.Sprintf("%v (context deadline exceeded, timeout %v)", err, timeout)
+	case errors.Is(ctx.Err(), context.Canceled):
+		return fmt.Sprintf("%v (context canceled: %v)", err, context.Cause(ctx))
+	default:
+		return err.Error()
+	}
+}
+
+// Must2 is Must for functions that return two values along with an error.
+func Must2[A, B any](testingT testthings.Terminator, fn func() (A, B, error)) (a A, b B) {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	if testingT != nil {
+		defer recoverAsFatal(testingT, func() {
+			var (
+				zeroA A
+				zeroB B
+			)
+			a, b = zeroA, zeroB
+		})
+	}
+
+	var err error
+	a, b, err = fn()
+	if err != nil {
+		fail(testingT, fmt.Sprintf("must! but: %v", err))
+	}
+	return a, b
+}
+
+// Must3 is Must for functions that return three values along with an error.
+func Must3[A, B, C any](testingT testthings.Terminator, fn func() (A, B, C, error)) (a A, b B, c C) {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	if testingT != nil {
+		defer recoverAsFatal(testingT, func() {
+			var (
+				zeroA A
+				zeroB B
+				zeroC C
+			)
+			a, b, c = zeroA, zeroB, zeroC
+		})
+	}
+
+	var err error
+	a, b, c, err = fn()
+	if err != nil {
+		fail(testingT, fmt.Sprintf("must! but: %v", err))
+	}
+	return a, b, c
+}
+
+// recoverAsFatal recovers any panic and fails the test with its value, calling
+// reset to clear the caller's return values. It must be deferred directly.
+func recoverAsFatal(testingT testthings.Terminator, reset func()) {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	if r := recover(); r != nil {
+		testingT.Fatal(fmt.Sprintf("must! but:\n%v", r))
+		reset()
+	}
+}
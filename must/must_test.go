@@ -1,9 +1,13 @@
 package must_test
 
 import (
+	"context"
 	"errors"
-	"sync"
+	"net"
+	"regexp"
+	"strings"
 	"testing"
+	"time"
 
 	"github.com/jahkeup/testthings/must"
 	"github.com/jahkeup/testthings/testerr"
@@ -32,50 +36,21 @@ func TestMust(t *testing.T) {
 				return 42, testerr.Expected
 			}
 
-			wg := &sync.WaitGroup{}
-			defer wg.Wait()
-
-			wg.Add(1)
-			go func() {
-				defer func() {
-					if r := recover(); r == nil {
-						t.Error("did not panic")
-					}
-					wg.Done()
-				}()
-				var actual int
-
-				actual = must.Must[int](nil, f)
-				if actual == 42 {
-					t.Error("shouldn't have even gotten here")
-				}
-			}()
+			must.Panic(t, func() {
+				actual := must.Must[int](nil, f)
+				t.Errorf("shouldn't have even gotten here, got %d", actual)
+			}, must.PanicMessage(regexp.QuoteMeta(testerr.Expected.Error())))
 		})
 
 		t.Run("func() T", func(t *testing.T) {
 			f := func() int {
 				panic("bai")
-				return 42 // shouldn't be returned
 			}
 
-			wg := &sync.WaitGroup{}
-			defer wg.Wait()
-
-			wg.Add(1)
-			go func() {
-				defer func() {
-					if r := recover(); r == nil {
-						t.Error("did not panic")
-					}
-					wg.Done()
-				}()
-				var actual int
-
-				actual = must.Must[int](nil, f)
-				if actual == 42 {
-					t.Error("shouldn't have even gotten here")
-				}
-			}()
+			must.Panic(t, func() {
+				actual := must.Must[int](nil, f)
+				t.Errorf("shouldn't have even gotten here, got %d", actual)
+			}, must.PanicMessage("bai"))
 		})
 	})
 }
@@ -119,19 +94,123 @@ func TestNewFooing(t *testing.T) {
 	}
 
 	t.Run("foo panic", func(t *testing.T) {
-		defer func() {
-			if r := recover(); r == nil {
-				t.Fatal("expected panic")
-			}
-		}()
+		must.Panic(t, func() {
+			// TODO: keep trying to remove the type parameter from this.. Go
+			// will get there, eventually.
+			var foo Foo = must.Must[Foo](nil, func() (*Foo, error) {
+				return NewFoo("im going to make Must panic", errorNum)
+			})
+			t.Errorf("should not get to here, got %v", foo)
+		})
+	})
+}
+
+func TestMust2(t *testing.T) {
+	host, port := must.Must2(t, func() (string, string, error) {
+		return net.SplitHostPort("127.0.0.1:8080")
+	})
+	if host != "127.0.0.1" || port != "8080" {
+		t.Errorf("unexpected split: %q %q", host, port)
+	}
+
+	t.Run("error", func(t *testing.T) {
+		f := &fatals{}
+		host, port := must.Must2(f, func() (string, string, error) {
+			return net.SplitHostPort("no port")
+		})
+		if len(f.msgs) != 1 {
+			t.Fatalf("should have failed once, got %q", f.msgs)
+		}
+		t.Logf("host=%q port=%q: %s", host, port, f.msgs[0])
+	})
+
+	t.Run("panic", func(t *testing.T) {
+		f := &fatals{}
+		host, port := must.Must2(f, func() (string, string, error) {
+			panic("bai")
+		})
+		if len(f.msgs) != 1 {
+			t.Fatalf("should have failed once, got %q", f.msgs)
+		}
+		if host != "" || port != "" {
+			t.Errorf("should return zero values, got %q %q", host, port)
+		}
+
+		must.Panic(t, func() {
+			must.Must2(nil, func() (int, int, error) {
+				return 1, 2, testerr.Expected
+			})
+		}, must.PanicMessage(regexp.QuoteMeta(testerr.Expected.Error())))
+	})
+}
+
+func TestMust3(t *testing.T) {
+	a, b, c := must.Must3(t, func() (int, string, bool, error) {
+		return 1, "two", true, nil
+	})
+	if a != 1 || b != "two" || !c {
+		t.Errorf("unexpected values: %v %v %v", a, b, c)
+	}
+
+	f := &fatals{}
+	a, b, c = must.Must3(f, func() (int, string, bool, error) {
+		panic("bai")
+	})
+	if len(f.msgs) != 1 {
+		t.Fatalf("should have failed once, got %q", f.msgs)
+	}
+	if a != 0 || b != "" || c {
+		t.Errorf("should return zero values, got %v %v %v", a, b, c)
+	}
+}
 
-		// TODO: keep trying to remove the type parameter from this.. Go will
-		// get there, eventually.
-		var foo Foo = must.Must[Foo](nil, func() (*Foo, error) {
-			return NewFoo("im going to make Must panic", errorNum)
+func TestMust_context(t *testing.T) {
+	var ctx context.Context
+	t.Run("scoped", func(t *testing.T) {
+		actual := must.Must[int](t, func(c context.Context) (int, error) {
+			ctx = c
+			return 42, c.Err()
 		})
+		if actual != 42 {
+			t.Fatal("not passing right")
+		}
+		if ctx.Err() != nil {
+			t.Fatal("context should be alive during the test")
+		}
+	})
+	if ctx.Err() == nil {
+		t.Fatal("context should be cancelled with the test")
+	}
+
+	t.Run("func(context.Context) *T", func(t *testing.T) {
+		foo := must.Must[Foo](t, func(ctx context.Context) (*Foo, error) {
+			return NewFoo("Foo With Context", 1)
+		})
+		if foo.FavoriteNumber != 1 {
+			t.Fatalf("unexpected foo: %v", foo)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		f := &fatals{}
+		must.MustTimeout[int](f, time.Millisecond, func(ctx context.Context) (int, error) {
+			<-ctx.Done()
+			return 0, ctx.Err()
+		})
+		if len(f.msgs) != 1 {
+			t.Fatalf("should have failed once, got %q", f.msgs)
+		}
+		if !strings.Contains(f.msgs[0], "deadline exceeded, timeout 1ms") {
+			t.Errorf("should report the deadline, got: %s", f.msgs[0])
+		}
+	})
 
-		t.Fatal("should not get to here")
-		foo.FavoriteNumber -= 1
+	t.Run("nil terminator", func(t *testing.T) {
+		must.Panic(t, func() {
+			must.MustTimeout[int](nil, time.Millisecond, func(ctx context.Context) (int, error) {
+				<-ctx.Done()
+				return 0, ctx.Err()
+			})
+		}, must.PanicMessage("deadline exceeded"))
 	})
 }
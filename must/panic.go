@@ -0,0 +1,144 @@
+package must
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"regexp"
+	"runtime/debug"
+
+	"github.com/jahkeup/testthings"
+)
+
+// Recovered is a value recovered from a panic along with the stack of the
+// panicking goroutine.
+type Recovered struct {
+	Value any
+	Stack []byte
+}
+
+// String formats the recovered value and its stack.
+func (r Recovered) String() string {
+	return fmt.Sprintf("%v\n%s", r.Value, r.Stack)
+}
+
+// PanicMatcher checks a value recovered from a panic, returning an error
+// describing why the value didn't match.
+type PanicMatcher func(value any) error
+
+// PanicType matches panic values of type P.
+func PanicType[P any]() PanicMatcher {
+	return func(value any) error {
+		if _, ok := value.(P); !ok {
+			return fmt.Errorf("panic value %T is not %v", value, reflect.TypeOf((*P)(nil)).Elem())
+		}
+		return nil
+	}
+}
+
+// PanicIs matches panic values that are errors matching target with errors.Is.
+// This is particularly useful with testerr tokens.
+func PanicIs(target error) PanicMatcher {
+	return func(value any) error {
+		err, ok := value.(error)
+		if !ok {
+			return fmt.Errorf("panic value %T is not an error", value)
+		}
+		if !errors.Is(err, target) {
+			return fmt.Errorf("panic error %q is not %q", err, target)
+		}
+		return nil
+	}
+}
+
+// PanicMessage matches panic values whose formatted message (as with %v)
+// matches the regular expression.
+func PanicMessage(expr string) PanicMatcher {
+	re := regexp.MustCompile(expr)
+	return func(value any) error {
+		msg := fmt.Sprint(value)
+		if !re.MatchString(msg) {
+			return fmt.Errorf("panic message %q does not match %q", msg, expr)
+		}
+		return nil
+	}
+}
+
+// Panic calls fn and requires that it panics, returning the recovered value
+// and stack. The recovered value must satisfy each of the matchers. If fn
+// doesn't panic or a matcher doesn't match, the test is failed. Alternatively,
+// nil may be passed for testingT in which case failures will panic.
+func Panic(testingT testthings.Terminator, fn func(), matchers ...PanicMatcher) Recovered {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	rec, panicked := capturePanic(fn)
+	if !panicked {
+		fail(testingT, "must panic! but: returned normally")
+		return rec
+	}
+
+	for _, match := range matchers {
+		if err := match(rec.Value); err != nil {
+			fail(testingT, fmt.Sprintf("must panic! but: %v\n%s", err, rec.Stack))
+			return rec
+		}
+	}
+
+	return rec
+}
+
+// NoPanic calls fn and fails the test, reporting the panic's value and stack,
+// if it panics. Alternatively, nil may be passed for testingT in which case
+// the panic is propagated.
+func NoPanic(testingT testthings.Terminator, fn func()) {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	if testingT == nil {
+		fn()
+		return
+	}
+
+	if rec, panicked := capturePanic(fn); panicked {
+		testingT.Fatal(fmt.Sprintf("must not panic! but: %v", rec))
+	}
+}
+
+// capturePanic calls fn and recovers its panic, if any.
+func capturePanic(fn func()) (rec Recovered, panicked bool) {
+	returned := false
+	defer func() {
+		if returned {
+			return
+		}
+		// NOTE: a nil value is still a panic (or, a runtime.Goexit in which
+		// case this never returns to the caller anyway).
+		rec = Recovered{Value: recover(), Stack: debug.Stack()}
+		panicked = true
+	}()
+
+	fn()
+	returned = true
+	return rec, false
+}
+
+// fail fails the test with the message, or panics when there's no testingT.
+func fail(testingT testthings.Terminator, msg string) {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	if testingT == nil {
+		panic(msg)
+	}
+	testingT.Fatal(msg)
+}
@@ -0,0 +1,84 @@
+package must_test
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/jahkeup/testthings/must"
+	"github.com/jahkeup/testthings/testerr"
+)
+
+// fatals records Fatal calls instead of stopping the test.
+type fatals struct {
+	msgs []string
+}
+
+func (f *fatals) Fatal(args ...any) {
+	f.msgs = append(f.msgs, fmt.Sprint(args...))
+}
+
+func TestPanic(t *testing.T) {
+	t.Run("value", func(t *testing.T) {
+		rec := must.Panic(t, func() { panic(testerr.Expected) },
+			must.PanicType[testerr.TestingError](),
+			must.PanicIs(testerr.Expected),
+			must.PanicMessage("^this error"),
+		)
+		if rec.Value != testerr.Expected {
+			t.Errorf("recovered %#v", rec.Value)
+		}
+		if !strings.Contains(string(rec.Stack), "panic_test.go") {
+			t.Errorf("stack should include the panicking function:\n%s", rec.Stack)
+		}
+	})
+
+	t.Run("no panic", func(t *testing.T) {
+		f := &fatals{}
+		must.Panic(f, func() {})
+		if len(f.msgs) != 1 {
+			t.Fatalf("should have failed once, got %q", f.msgs)
+		}
+	})
+
+	t.Run("mismatch", func(t *testing.T) {
+		matchers := map[string]must.PanicMatcher{
+			"type":    must.PanicType[error](),
+			"is":      must.PanicIs(testerr.Expected),
+			"message": must.PanicMessage("^nope$"),
+		}
+		for name, matcher := range matchers {
+			t.Run(name, func(t *testing.T) {
+				f := &fatals{}
+				must.Panic(f, func() { panic("bai") }, matcher)
+				if len(f.msgs) != 1 {
+					t.Fatalf("should have failed once, got %q", f.msgs)
+				}
+				t.Log(f.msgs[0])
+			})
+		}
+	})
+
+	t.Run("nil terminator", func(t *testing.T) {
+		must.Panic(t, func() {
+			must.Panic(nil, func() {})
+		}, must.PanicMessage("returned normally"))
+	})
+}
+
+func TestNoPanic(t *testing.T) {
+	must.NoPanic(t, func() {})
+
+	f := &fatals{}
+	must.NoPanic(f, func() { panic("bai") })
+	if len(f.msgs) != 1 {
+		t.Fatalf("should have failed once, got %q", f.msgs)
+	}
+	if !strings.Contains(f.msgs[0], "bai") || !strings.Contains(f.msgs[0], "panic_test.go") {
+		t.Errorf("should report value and stack, got:\n%s", f.msgs[0])
+	}
+
+	must.Panic(t, func() {
+		must.NoPanic(nil, func() { panic("bai") })
+	}, must.PanicMessage("^bai$"))
+}
@@ -0,0 +1,201 @@
+package must
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/jahkeup/testthings"
+)
+
+// DefaultPollTimeout limits polling when the context has no deadline of its
+// own. Consistently polls for this long beyond its duration.
+var DefaultPollTimeout = 10 * time.Second
+
+// PollOption configures how Eventually and Consistently poll their condition.
+type PollOption func(*pollOptions)
+
+type pollOptions struct {
+	timeout time.Duration
+	backoff func(attempt int) time.Duration
+}
+
+// PollTimeout limits polling to the duration, in addition to the deadline of
+// the context (if any).
+func PollTimeout(d time.Duration) PollOption {
+	return func(o *pollOptions) { o.timeout = d }
+}
+
+// PollInterval waits the same duration between each attempt.
+func PollInterval(d time.Duration) PollOption {
+	return func(o *pollOptions) {
+		o.backoff = func(int) time.Duration { return d }
+	}
+}
+
+// PollBackoff waits exponentially longer between each attempt, starting with
+// initial and multiplying by factor up to max.
+func PollBackoff(initial, max time.Duration, factor float64) PollOption {
+	return func(o *pollOptions) {
+		o.backoff = func(attempt int) time.Duration {
+			d := float64(initial)
+			for i := 1; i < attempt && d < float64(max); i++ {
+				d *= factor
+			}
+			if d > float64(max) {
+				return max
+			}
+			return time.Duration(d)
+		}
+	}
+}
+
+func newPollOptions(opts []PollOption) pollOptions {
+	options := pollOptions{}
+	PollBackoff(10*time.Millisecond, 250*time.Millisecond, 2)(&options)
+	for _, opt := range opts {
+		opt(&options)
+	}
+	return options
+}
+
+// pollContext limits the context to the options' timeout, applying the
+// DefaultPollTimeout (after the minimum polling duration) if the context would
+// otherwise poll forever.
+func pollContext(testingT testthings.Terminator, ctx context.Context, options pollOptions, minimum time.Duration) (context.Context, context.CancelFunc) {
+	if ctx == nil {
+		ctx = context.Background()
+		if cleanuper, ok := testingT.(testthings.Cleanuper); ok {
+			ctx = testthings.C(cleanuper)
+		}
+	}
+
+	timeout := options.timeout
+	if _, hasDeadline := ctx.Deadline(); !hasDeadline && timeout <= 0 {
+		timeout = minimum + DefaultPollTimeout
+	}
+	if timeout > 0 {
+		return context.WithTimeout(ctx, timeout)
+	}
+	return context.WithCancel(ctx)
+}
+
+// Eventually calls the condition until it succeeds, returning its value. The
+// condition is retried, waiting between attempts as configured by the options,
+// while it errors or panics. If the context (limited by PollTimeout, or
+// DefaultPollTimeout without a deadline) is done first, the test is failed
+// with the last error and the number of attempts. Context-taking conditions
+// are given the polling context. Alternatively, nil may be passed for testingT
+// in which case failures will cause the program to panic at runtime.
+func Eventually[T any, F Mustable[T]](testingT testthings.Terminator, ctx context.Context, condition F, opts ...PollOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	options := newPollOptions(opts)
+	ctx, cancel := pollContext(testingT, ctx, options, 0)
+	defer cancel()
+
+	start := time.Now()
+	var lastErr error
+	for attempt := 1; ; attempt++ {
+		value, err := try[T](ctx, condition)
+		if err == nil {
+			return value
+		}
+		lastErr = err
+
+		if !pollWait(ctx, options.backoff(attempt)) {
+			var zero T
+			fail(testingT, fmt.Sprintf("eventually! but: gave up after %d attempts in %v (%v), last error: %v",
+				attempt, time.Since(start).Round(time.Millisecond), ctx.Err(), lastErr))
+			return zero
+		}
+	}
+}
+
+// Consistently calls the condition for the whole duration, failing the test
+// as soon as it errors or panics. The condition is called again after waiting
+// as configured by the options, the last value is returned. If the context
+// (limited by PollTimeout, or the duration and DefaultPollTimeout without a
+// deadline) is done before the duration has passed, the test is also failed.
+// Context-taking conditions are given the polling context. Alternatively, nil
+// may be passed for testingT in which case failures will cause the program to
+// panic at runtime.
+func Consistently[T any, F Mustable[T]](testingT testthings.Terminator, ctx context.Context, duration time.Duration, condition F, opts ...PollOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	options := newPollOptions(opts)
+	ctx, cancel := pollContext(testingT, ctx, options, duration)
+	defer cancel()
+
+	start := time.Now()
+	var value T
+	for attempt := 1; ; attempt++ {
+		var err error
+		value, err = try[T](ctx, condition)
+		if err != nil {
+			fail(testingT, fmt.Sprintf("consistently! but: attempt %d failed after %v: %v",
+				attempt, time.Since(start).Round(time.Millisecond), err))
+			return value
+		}
+
+		remaining := duration - time.Since(start)
+		if remaining <= 0 {
+			return value
+		}
+
+		wait := options.backoff(attempt)
+		if wait > remaining {
+			wait = remaining
+		}
+		if !pollWait(ctx, wait) {
+			fail(testingT, fmt.Sprintf("consistently! but: stopped after %d attempts in %v of %v: %v",
+				attempt, time.Since(start).Round(time.Millisecond), duration, ctx.Err()))
+			return value
+		}
+	}
+}
+
+// pollWait waits for the duration, returning false if the context is done
+// first.
+func pollWait(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
+
+// try calls the condition through Must's dispatch with the polling context,
+// returning its value or the failure (or panic) it reported.
+func try[T any, F Mustable[T]](ctx context.Context, condition F) (T, error) {
+	a := &attempt{}
+	ret := mustWithin[T](a, ctx, 0, condition)
+	return ret, a.err
+}
+
+// attempt is the Terminator of a single attempt, recording its failure
+// instead of failing the test.
+type attempt struct {
+	err error
+}
+
+func (a *attempt) Fatal(args ...any) {
+	if a.err == nil {
+		msg := strings.TrimSpace(strings.TrimPrefix(fmt.Sprint(args...), "must! but:"))
+		a.err = errors.New(msg)
+	}
+}
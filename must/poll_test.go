@@ -0,0 +1,141 @@
+package must_test
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jahkeup/testthings"
+	"github.com/jahkeup/testthings/must"
+	"github.com/jahkeup/testthings/testerr"
+)
+
+func TestEventually(t *testing.T) {
+	t.Run("succeeds", func(t *testing.T) {
+		attempts := 0
+		actual := must.Eventually[int](t, testthings.C(t), func() (int, error) {
+			attempts++
+			if attempts < 3 {
+				return 0, testerr.TODO
+			}
+			return attempts, nil
+		}, must.PollInterval(time.Millisecond))
+		if actual != 3 {
+			t.Errorf("should return the successful value, got %d", actual)
+		}
+	})
+
+	t.Run("panics are retried", func(t *testing.T) {
+		attempts := 0
+		must.Eventually[int](t, nil, func() int {
+			attempts++
+			if attempts < 2 {
+				panic("not yet")
+			}
+			return attempts
+		}, must.PollInterval(time.Millisecond))
+	})
+
+	t.Run("deadline", func(t *testing.T) {
+		parent, _ := testthings.NewContext(t)
+		ctx, cancel := context.WithTimeout(parent, 20*time.Millisecond)
+		defer cancel()
+
+		f := &fatals{}
+		must.Eventually[int](f, ctx, func(ctx context.Context) (int, error) {
+			return 0, testerr.Expected
+		}, must.PollBackoff(time.Millisecond, 5*time.Millisecond, 2))
+		if len(f.msgs) != 1 {
+			t.Fatalf("should have failed once, got %q", f.msgs)
+		}
+		t.Log(f.msgs[0])
+		for _, expected := range []string{"gave up after", "attempts", "deadline exceeded", testerr.Expected.Error()} {
+			if !strings.Contains(f.msgs[0], expected) {
+				t.Errorf("should contain %q", expected)
+			}
+		}
+	})
+
+	t.Run("copy unsafe", func(t *testing.T) {
+		f := &fatals{}
+		must.Eventually[counter](f, nil, func() *counter { return &counter{} },
+			must.PollTimeout(5*time.Millisecond), must.PollInterval(time.Millisecond))
+		if len(f.msgs) != 1 || !strings.Contains(f.msgs[0], "use MustPtr") {
+			t.Fatalf("should refuse to copy, like Must, got %q", f.msgs)
+		}
+	})
+
+	t.Run("timeout", func(t *testing.T) {
+		must.Panic(t, func() {
+			must.Eventually[int](nil, nil, func() (*int, error) {
+				return nil, nil
+			}, must.PollTimeout(5*time.Millisecond))
+		}, must.PanicMessage(testerr.NilPointer.Error()))
+	})
+}
+
+func TestConsistently(t *testing.T) {
+	t.Run("holds", func(t *testing.T) {
+		attempts := 0
+		start := time.Now()
+		actual := must.Consistently[int](t, nil, 20*time.Millisecond, func() (int, error) {
+			attempts++
+			return attempts, nil
+		}, must.PollInterval(time.Millisecond))
+		if elapsed := time.Since(start); elapsed < 20*time.Millisecond {
+			t.Errorf("should poll for the whole duration, took %v", elapsed)
+		}
+		if actual != attempts || attempts < 2 {
+			t.Errorf("should return the last value, got %d of %d", actual, attempts)
+		}
+	})
+
+	t.Run("breaks", func(t *testing.T) {
+		attempts := 0
+		f := &fatals{}
+		must.Consistently[int](f, nil, time.Second, func() (int, error) {
+			attempts++
+			if attempts == 3 {
+				return 0, testerr.Expected
+			}
+			return attempts, nil
+		}, must.PollInterval(time.Millisecond))
+		if len(f.msgs) != 1 || !strings.Contains(f.msgs[0], "attempt 3 failed") {
+			t.Fatalf("should have failed on the third attempt, got %q", f.msgs)
+		}
+	})
+
+	t.Run("longer than the default timeout", func(t *testing.T) {
+		defaultTimeout := must.DefaultPollTimeout
+		must.DefaultPollTimeout = 20 * time.Millisecond
+		t.Cleanup(func() { must.DefaultPollTimeout = defaultTimeout })
+
+		duration := 50 * time.Millisecond
+		f := &fatals{}
+		start := time.Now()
+		must.Consistently[int](f, nil, duration, func(ctx context.Context) (int, error) {
+			if deadline, ok := ctx.Deadline(); !ok || deadline.Before(start.Add(duration)) {
+				return 0, fmt.Errorf("polling deadline %v is before the duration ends", deadline)
+			}
+			return 1, nil
+		}, must.PollInterval(5*time.Millisecond))
+		if len(f.msgs) != 0 {
+			t.Fatalf("should hold for the whole duration, got %q", f.msgs)
+		}
+		if elapsed := time.Since(start); elapsed < duration {
+			t.Errorf("should poll for the whole duration, took %v", elapsed)
+		}
+	})
+
+	t.Run("context done", func(t *testing.T) {
+		f := &fatals{}
+		must.Consistently[int](f, testerr.CanceledContext(t, nil), time.Second, func() int {
+			return 1
+		})
+		if len(f.msgs) != 1 || !strings.Contains(f.msgs[0], "context canceled") {
+			t.Fatalf("should have failed, got %q", f.msgs)
+		}
+	})
+}
@@ -0,0 +1,80 @@
+package must
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/jahkeup/testthings"
+)
+
+// PtrMustable are the function shapes that construct a *T.
+type PtrMustable[T any] interface {
+	~func(*T) | ~func() *T | ~func() (*T, error) | ~func(context.Context) (*T, error)
+}
+
+// MustPtr is Must for pointer constructors, returning the constructed pointer
+// as is. Use it for types that must not be copied, like those containing a
+// sync.Mutex, or that otherwise rely on their identity. Must refuses to copy
+// these values.
+func MustPtr[T any, F PtrMustable[T]](testingT testthings.Terminator, mustable F) (ret *T) {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	if testingT != nil {
+		defer recoverAsFatal(testingT, func() {
+			ret = nil
+		})
+	}
+
+	return constructPtr[T](testingT, nil, 0, any(mustable))
+}
+
+var lockerType = reflect.TypeOf((*sync.Locker)(nil)).Elem()
+
+// copyUnsafe returns an error when values of the type must not be copied. Like
+// go vet's copylocks check, these are types that contain a sync.Locker by
+// value, which includes the sync and sync/atomic types marked as noCopy.
+func copyUnsafe(t reflect.Type) error {
+	if !containsLock(t) {
+		return nil
+	}
+
+	if t.Kind() == reflect.Struct && !isLock(t) {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if containsLock(field.Type) {
+				return fmt.Errorf("%v contains %v (field %s) which must not be copied, use MustPtr", t, field.Type, field.Name)
+			}
+		}
+	}
+
+	return fmt.Errorf("%v must not be copied, use MustPtr", t)
+}
+
+func isLock(t reflect.Type) bool {
+	return t.Kind() == reflect.Struct && reflect.PointerTo(t).Implements(lockerType)
+}
+
+func containsLock(t reflect.Type) bool {
+	if isLock(t) {
+		return true
+	}
+
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			if containsLock(t.Field(i).Type) {
+				return true
+			}
+		}
+	case reflect.Array:
+		return t.Len() > 0 && containsLock(t.Elem())
+	}
+
+	return false
+}
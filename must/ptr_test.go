@@ -0,0 +1,125 @@
+package must_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/jahkeup/testthings/must"
+	"github.com/jahkeup/testthings/testerr"
+)
+
+type counter struct {
+	mu sync.Mutex
+	n  int
+}
+
+type nestedCounter struct {
+	Name    string
+	counter [1]counter
+}
+
+type atomicCounter struct {
+	n atomic.Int64
+}
+
+func TestMust_pointers(t *testing.T) {
+	t.Run("func() *T", func(t *testing.T) {
+		foo := must.Must[Foo](t, func() *Foo {
+			return &Foo{Name: "Foo The Pointed", FavoriteNumber: 7}
+		})
+		if foo.FavoriteNumber != 7 {
+			t.Fatalf("should return the constructed value, got %#v", foo)
+		}
+	})
+
+	t.Run("nil", func(t *testing.T) {
+		shapes := map[string]func(f *fatals) Foo{
+			"func() *T": func(f *fatals) Foo {
+				return must.Must[Foo](f, func() *Foo { return nil })
+			},
+			"func() (*T, error)": func(f *fatals) Foo {
+				return must.Must[Foo](f, func() (*Foo, error) { return nil, nil })
+			},
+			"func() (*T, error) errors": func(f *fatals) Foo {
+				return must.Must[Foo](f, func() (*Foo, error) { return nil, testerr.Expected })
+			},
+			"func(context.Context) (*T, error)": func(f *fatals) Foo {
+				return must.Must[Foo](f, func(context.Context) (*Foo, error) { return nil, nil })
+			},
+		}
+
+		for name, shape := range shapes {
+			t.Run(name, func(t *testing.T) {
+				f := &fatals{}
+				foo := shape(f)
+				if len(f.msgs) != 1 {
+					t.Fatalf("should have failed once, got %q", f.msgs)
+				}
+				if foo != (Foo{}) {
+					t.Errorf("should return the zero value, got %#v", foo)
+				}
+			})
+		}
+	})
+
+	t.Run("copy unsafe", func(t *testing.T) {
+		checks := map[string]func(f *fatals){
+			"mutex": func(f *fatals) {
+				must.Must[counter](f, func() *counter { return &counter{} })
+			},
+			"nested": func(f *fatals) {
+				must.Must[nestedCounter](f, func(c *nestedCounter) { c.Name = "nested" })
+			},
+			"atomic": func(f *fatals) {
+				must.Must[atomicCounter](f, func() (*atomicCounter, error) { return &atomicCounter{}, nil })
+			},
+		}
+
+		for name, check := range checks {
+			t.Run(name, func(t *testing.T) {
+				f := &fatals{}
+				check(f)
+				if len(f.msgs) != 1 || !strings.Contains(f.msgs[0], "MustPtr") {
+					t.Fatalf("should have failed suggesting MustPtr, got %q", f.msgs)
+				}
+				t.Log(f.msgs[0])
+			})
+		}
+	})
+}
+
+func TestMustPtr(t *testing.T) {
+	original := &counter{n: 1}
+	actual := must.MustPtr[counter](t, func() (*counter, error) {
+		return original, nil
+	})
+	if actual != original {
+		t.Fatal("should return the constructed pointer")
+	}
+
+	injected := must.MustPtr[counter](t, func(c *counter) {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		c.n = 42
+	})
+	if injected.n != 42 {
+		t.Fatalf("should return the injected pointer, got %d", injected.n)
+	}
+
+	f := &fatals{}
+	if ptr := must.MustPtr[counter](f, func() *counter { return nil }); ptr != nil {
+		t.Error("should be nil")
+	}
+	if len(f.msgs) != 1 {
+		t.Fatalf("should have failed once, got %q", f.msgs)
+	}
+
+	must.Panic(t, func() {
+		must.MustPtr[counter](nil, func() (*counter, error) {
+			return nil, testerr.Expected
+		})
+	}, must.PanicMessage("expected"))
+}
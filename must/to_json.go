@@ -0,0 +1,195 @@
+package must
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/jahkeup/testthings"
+)
+
+// ToJSON will marshal the value into json text. If the value does not
+// marshal, the test will be failed. Alternatively, this function may be used
+// in initialization contexts by passing nil for the testingT object in which
+// case errors will cause the program to panic at runtime.
+func ToJSON[T any](testingT testthings.Terminator, v T) []byte {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	return Must[[]byte](testingT, func() []byte {
+		text, err := json.Marshal(v)
+		if err != nil {
+			fail(testingT, fmt.Sprintf("cannot marshal %T into json: %v", v, err))
+		}
+		return text
+	})
+}
+
+// JSONRoundTrip marshals the value into json text and back into a new value,
+// failing the test when anything is lost or changed along the way: both the
+// json text of the two values and the values themselves are compared. The
+// round tripped value is returned. Alternatively, nil may be passed for
+// testingT in which case differences will cause the program to panic at
+// runtime.
+func JSONRoundTrip[T any](testingT testthings.Terminator, v T, opts ...JSONCompareOption) T {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	var options jsonCompareOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	return Must[T](testingT, func() T {
+		out := new(T)
+
+		text, err := json.Marshal(v)
+		if err != nil {
+			fail(testingT, fmt.Sprintf("json round trip! but: cannot marshal %T: %v", v, err))
+			return *out
+		}
+		if err := decodeJSON(text, out); err != nil {
+			fail(testingT, fmt.Sprintf("json round trip! but: cannot unmarshal %T: %v", out, err))
+			return *out
+		}
+		roundTripped, err := json.Marshal(*out)
+		if err != nil {
+			fail(testingT, fmt.Sprintf("json round trip! but: cannot marshal round tripped %T: %v", out, err))
+			return *out
+		}
+
+		var msgs []string
+		// both were marshaled, so they're known to decode.
+		before, _ := canonicalJSON(text)
+		after, _ := canonicalJSON(roundTripped)
+		if diffs := diffJSON(nil, before, after, options); len(diffs) > 0 {
+			msgs = append(msgs, fmt.Sprintf("json changed:\n%s", formatDiffs(diffs)))
+		}
+		if diffs := diffValues(nil, reflect.ValueOf(&v).Elem(), reflect.ValueOf(out).Elem(), options); len(diffs) > 0 {
+			msgs = append(msgs, fmt.Sprintf("%T changed:\n%s", v, formatDiffs(diffs)))
+		}
+
+		if len(msgs) > 0 {
+			fail(testingT, fmt.Sprintf("json round trip! but:\n%s", strings.Join(msgs, "\n")))
+		}
+		return *out
+	})
+}
+
+// valueDiff is a difference at a field path between two Go values.
+type valueDiff struct {
+	Path   string
+	Before reflect.Value
+	After  reflect.Value
+}
+
+func (d valueDiff) String() string {
+	return fmt.Sprintf("%s: %s became %s", d.Path, formatValue(d.Before), formatValue(d.After))
+}
+
+func formatValue(v reflect.Value) string {
+	if !v.IsValid() {
+		return "<invalid>"
+	}
+	return fmt.Sprintf("%#v", v)
+}
+
+// diffValues compares the values, descending into structs, maps, and slices
+// to find the fields that differ.
+func diffValues(path []string, before, after reflect.Value, options jsonCompareOptions) []valueDiff {
+	dotted := strings.Join(path, ".")
+	if options.ignoreFields[dotted] {
+		return nil
+	}
+	if valuesEqual(before, after) {
+		return nil
+	}
+
+	child := func(key string) []string {
+		return append(path[:len(path):len(path)], key)
+	}
+	here := []valueDiff{{Path: dottedOrRoot(dotted), Before: before, After: after}}
+
+	switch before.Kind() {
+	case reflect.Struct:
+		var diffs []valueDiff
+		for i := 0; i < before.NumField(); i++ {
+			name := before.Type().Field(i).Name
+			diffs = append(diffs, diffValues(child(name), before.Field(i), after.Field(i), options)...)
+		}
+		return diffs
+
+	case reflect.Pointer, reflect.Interface:
+		if before.IsNil() || after.IsNil() || before.Elem().Type() != after.Elem().Type() {
+			return here
+		}
+		return diffValues(path, before.Elem(), after.Elem(), options)
+
+	case reflect.Slice, reflect.Array:
+		if before.Len() != after.Len() {
+			return here
+		}
+		var diffs []valueDiff
+		for i := 0; i < before.Len(); i++ {
+			diffs = append(diffs, diffValues(child(fmt.Sprintf("[%d]", i)), before.Index(i), after.Index(i), options)...)
+		}
+		return diffs
+
+	case reflect.Map:
+		if before.Len() != after.Len() {
+			return here
+		}
+		keys := before.MapKeys()
+		sort.Slice(keys, func(i, j int) bool {
+			return fmt.Sprint(keys[i]) < fmt.Sprint(keys[j])
+		})
+		var diffs []valueDiff
+		for _, k := range keys {
+			name := fmt.Sprintf("[%v]", k)
+			av := after.MapIndex(k)
+			if !av.IsValid() {
+				diffs = append(diffs, valueDiff{Path: dottedOrRoot(strings.Join(child(name), ".")), Before: before.MapIndex(k), After: av})
+				continue
+			}
+			diffs = append(diffs, diffValues(child(name), before.MapIndex(k), av, options)...)
+		}
+		return diffs
+	}
+
+	return here
+}
+
+func dottedOrRoot(dotted string) string {
+	if dotted == "" {
+		return "(value)"
+	}
+	return dotted
+}
+
+// valuesEqual compares the values using their Equal method, if they have one
+// (like time.Time), otherwise comparing them deeply.
+func valuesEqual(a, b reflect.Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+
+	if a.CanInterface() && b.CanInterface() {
+		if eq := a.MethodByName("Equal"); eq.IsValid() &&
+			eq.Type().NumIn() == 1 && eq.Type().In(0) == a.Type() &&
+			eq.Type().NumOut() == 1 && eq.Type().Out(0).Kind() == reflect.Bool {
+			return eq.Call([]reflect.Value{b})[0].Bool()
+		}
+		return reflect.DeepEqual(a.Interface(), b.Interface())
+	}
+
+	// unexported fields can't be compared directly, but they can be formatted.
+	return fmt.Sprintf("%#v", a) == fmt.Sprintf("%#v", b)
+}
@@ -0,0 +1,75 @@
+package must_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/jahkeup/testthings/must"
+)
+
+type contract struct {
+	ID        string            `json:"id"`
+	CreatedAt time.Time         `json:"created_at"`
+	Tags      map[string]string `json:"tags,omitempty"`
+	Secret    string            `json:"-"`
+	Lossy     lossy             `json:"lossy"`
+}
+
+// lossy only keeps its value when it's a multiple of ten.
+type lossy int
+
+func (l lossy) MarshalJSON() ([]byte, error) {
+	return json.Marshal(int(l) / 10 * 10)
+}
+
+func TestToJSON(t *testing.T) {
+	text := must.ToJSON(t, map[string]int{"a": 1})
+	if string(text) != `{"a":1}` {
+		t.Errorf("unexpected json: %s", text)
+	}
+
+	must.Panic(t, func() {
+		must.ToJSON(nil, func() {})
+	}, must.PanicMessage("cannot marshal func"))
+}
+
+func TestJSONRoundTrip(t *testing.T) {
+	t.Run("lossless", func(t *testing.T) {
+		original := contract{
+			ID:        "abc",
+			CreatedAt: time.Now(), // has a monotonic clock reading, lost in json
+			Tags:      map[string]string{"a": "b"},
+			Lossy:     20,
+		}
+		actual := must.JSONRoundTrip(t, original)
+		if actual.ID != original.ID || !actual.CreatedAt.Equal(original.CreatedAt) {
+			t.Errorf("unexpected round trip: %#v", actual)
+		}
+	})
+
+	t.Run("lossy", func(t *testing.T) {
+		f := &fatals{}
+		must.JSONRoundTrip(f, contract{ID: "abc", Secret: "hunter2", Lossy: 25})
+		if len(f.msgs) != 1 {
+			t.Fatalf("should have failed once, got %q", f.msgs)
+		}
+		t.Log(f.msgs[0])
+		for _, expected := range []string{
+			`Secret: "hunter2" became ""`,
+			`Lossy: 25 became 20`,
+		} {
+			if !strings.Contains(f.msgs[0], expected) {
+				t.Errorf("should contain %q", expected)
+			}
+		}
+		if strings.Contains(f.msgs[0], "json changed") {
+			t.Error("the json itself is stable, only the value changed")
+		}
+	})
+
+	t.Run("ignored", func(t *testing.T) {
+		must.JSONRoundTrip(t, contract{ID: "abc", Secret: "hunter2"}, must.IgnoreFields("Secret"))
+	})
+}
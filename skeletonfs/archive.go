@@ -0,0 +1,269 @@
+package skeletonfs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"strings"
+	"testing/fstest"
+)
+
+// OpenArchive reads the archive file as a skeleton, by its extension: .txtar,
+// .tar, .tar.gz (or .tgz), or .zip.
+func OpenArchive(name string) (ReadLinkFS, error) {
+	var read func(data []byte) (ReadLinkFS, error)
+	switch {
+	case strings.HasSuffix(name, ".txtar"):
+		read = ReadTxtar
+	case strings.HasSuffix(name, ".tar"):
+		read = func(data []byte) (ReadLinkFS, error) { return ReadTar(bytes.NewReader(data)) }
+	case strings.HasSuffix(name, ".tar.gz"), strings.HasSuffix(name, ".tgz"):
+		read = func(data []byte) (ReadLinkFS, error) { return ReadTarGz(bytes.NewReader(data)) }
+	case strings.HasSuffix(name, ".zip"):
+		read = func(data []byte) (ReadLinkFS, error) { return ReadZip(bytes.NewReader(data), int64(len(data))) }
+	default:
+		return nil, fmt.Errorf("archive %q: unsupported extension, use .txtar, .tar, .tar.gz, .tgz, or .zip", name)
+	}
+
+	data, err := os.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	return read(data)
+}
+
+// ReadTar reads the tar archive as a skeleton, with its directories, regular
+// files, symlinks, and their modes and modification times. Hard links are read
+// as copies of their target, other types of entries are skipped.
+func ReadTar(r io.Reader) (ReadLinkFS, error) {
+	mapFS := fstest.MapFS{}
+	tr := tar.NewReader(r)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("tar: %w", err)
+		}
+
+		name, err := archiveName(hdr.Name)
+		if err != nil {
+			return nil, fmt.Errorf("tar: %w", err)
+		}
+		if name == "." {
+			continue
+		}
+
+		mode := hdr.FileInfo().Mode()
+		file := &fstest.MapFile{Mode: mode.Type() | mode.Perm(), ModTime: hdr.ModTime}
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+		case tar.TypeReg:
+			file.Data, err = io.ReadAll(tr)
+			if err != nil {
+				return nil, fmt.Errorf("tar: %q: %w", name, err)
+			}
+		case tar.TypeSymlink:
+			file.Data = []byte(hdr.Linkname)
+		case tar.TypeLink:
+			target, err := archiveName(hdr.Linkname)
+			if err != nil {
+				return nil, fmt.Errorf("tar: %q: %w", name, err)
+			}
+			linked, ok := mapFS[target]
+			if !ok {
+				return nil, fmt.Errorf("tar: %q: hard link to missing %q", name, target)
+			}
+			file.Data, file.Mode = linked.Data, linked.Mode
+		default:
+			continue
+		}
+		mapFS[name] = file
+	}
+	return linkMapFS{mapFS}, nil
+}
+
+// ReadTarGz reads the gzip compressed tar archive as a skeleton, see ReadTar.
+func ReadTarGz(r io.Reader) (ReadLinkFS, error) {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("gzip: %w", err)
+	}
+	defer gz.Close()
+	return ReadTar(gz)
+}
+
+// ReadZip reads the zip archive as a skeleton, with its directories, regular
+// files, symlinks, and their modes and modification times.
+func ReadZip(r io.ReaderAt, size int64) (ReadLinkFS, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("zip: %w", err)
+	}
+
+	mapFS := fstest.MapFS{}
+	for _, f := range zr.File {
+		name, err := archiveName(f.Name)
+		if err != nil {
+			return nil, fmt.Errorf("zip: %w", err)
+		}
+		if name == "." {
+			continue
+		}
+
+		mode := f.Mode()
+		if strings.HasSuffix(f.Name, "/") {
+			mode |= fs.ModeDir
+		}
+		file := &fstest.MapFile{Mode: mode.Type() | mode.Perm(), ModTime: f.Modified}
+		switch {
+		case mode.IsDir():
+		case mode.IsRegular(), isSymlink(mode):
+			// symlinks hold their target as their content.
+			file.Data, err = readZipFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("zip: %q: %w", name, err)
+			}
+		default:
+			continue
+		}
+		mapFS[name] = file
+	}
+	return linkMapFS{mapFS}, nil
+}
+
+func readZipFile(f *zip.File) ([]byte, error) {
+	rc, err := f.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer rc.Close()
+	return io.ReadAll(rc)
+}
+
+// WriteTar writes fsys as a tar archive, with its directories, regular files,
+// symlinks (when fsys is a ReadLinkFS, like DirFS), modes, and modification
+// times. Ownership isn't written.
+func WriteTar(w io.Writer, fsys fs.FS) error {
+	tw := tar.NewWriter(w)
+	err := walkArchive(fsys, func(p string, info fs.FileInfo, link string, data []byte) error {
+		hdr, err := tar.FileInfoHeader(info, link)
+		if err != nil {
+			return err
+		}
+		hdr.Name = p
+		if info.IsDir() {
+			hdr.Name += "/"
+		}
+		hdr.Uid, hdr.Gid, hdr.Uname, hdr.Gname = 0, 0, "", ""
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("tar: %w", err)
+	}
+	return tw.Close()
+}
+
+// WriteTarGz writes fsys as a gzip compressed tar archive, see WriteTar.
+func WriteTarGz(w io.Writer, fsys fs.FS) error {
+	gz := gzip.NewWriter(w)
+	if err := WriteTar(gz, fsys); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// WriteZip writes fsys as a zip archive, with its directories, regular files,
+// symlinks (when fsys is a ReadLinkFS, like DirFS), modes, and modification
+// times.
+func WriteZip(w io.Writer, fsys fs.FS) error {
+	zw := zip.NewWriter(w)
+	err := walkArchive(fsys, func(p string, info fs.FileInfo, link string, data []byte) error {
+		hdr, err := zip.FileInfoHeader(info)
+		if err != nil {
+			return err
+		}
+		hdr.Name = p
+		if info.IsDir() {
+			hdr.Name += "/"
+		} else if info.Mode().IsRegular() {
+			hdr.Method = zip.Deflate
+		}
+
+		fw, err := zw.CreateHeader(hdr)
+		if err != nil {
+			return err
+		}
+		if link != "" {
+			data = []byte(link)
+		}
+		_, err = fw.Write(data)
+		return err
+	})
+	if err != nil {
+		return fmt.Errorf("zip: %w", err)
+	}
+	return zw.Close()
+}
+
+// walkArchive calls fn for each directory, regular file, and symlink in fsys
+// with the file's data or the symlink's target.
+func walkArchive(fsys fs.FS, fn func(p string, info fs.FileInfo, link string, data []byte) error) error {
+	return fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if p == "." {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		switch {
+		case info.IsDir():
+			return fn(p, info, "", nil)
+		case info.Mode().IsRegular():
+			data, err := fs.ReadFile(fsys, p)
+			if err != nil {
+				return err
+			}
+			return fn(p, info, "", data)
+		case isSymlink(info.Mode()):
+			linkFS, ok := fsys.(ReadLinkFS)
+			if !ok {
+				return fmt.Errorf("%q: symlink in a fs.FS without ReadLink", p)
+			}
+			link, err := linkFS.ReadLink(p)
+			if err != nil {
+				return err
+			}
+			return fn(p, info, link, nil)
+		}
+		return fmt.Errorf("%q: unsupported file type: %v", p, info.Mode().Type())
+	})
+}
+
+// archiveName cleans the name of an archive's entry, which must stay within
+// the archive.
+func archiveName(name string) (string, error) {
+	cleaned := path.Clean(strings.TrimPrefix(strings.TrimSuffix(name, "/"), "./"))
+	if !fs.ValidPath(cleaned) {
+		return "", fmt.Errorf("invalid entry name %q", name)
+	}
+	return cleaned, nil
+}
@@ -0,0 +1,116 @@
+package skeletonfs_test
+
+import (
+	"bytes"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jahkeup/testthings/skeletonfs"
+)
+
+var archivedTree = skeletonfs.Tree{
+	"etc/app.conf": "port=8080\n",
+	"bin/app":      skeletonfs.File{Contents: "#!/bin/sh\n", Mode: 0750},
+	"var/log":      skeletonfs.Dir{Mode: 0700},
+	"current":      skeletonfs.Symlink("releases/v2"),
+	"releases/v2":  skeletonfs.Tree{"VERSION": "v2"},
+}
+
+func TestArchives(t *testing.T) {
+	srcDir := skeletonfs.SkeletonFS(archivedTree.FS()).InstallTemp(t)
+	src := skeletonfs.DirFS(srcDir)
+
+	for _, tc := range []struct {
+		ext   string
+		write func(io.Writer, fs.FS) error
+		read  func([]byte) (skeletonfs.ReadLinkFS, error)
+	}{
+		{
+			ext:   ".tar",
+			write: skeletonfs.WriteTar,
+			read:  func(data []byte) (skeletonfs.ReadLinkFS, error) { return skeletonfs.ReadTar(bytes.NewReader(data)) },
+		},
+		{
+			ext:   ".tar.gz",
+			write: skeletonfs.WriteTarGz,
+			read:  func(data []byte) (skeletonfs.ReadLinkFS, error) { return skeletonfs.ReadTarGz(bytes.NewReader(data)) },
+		},
+		{
+			ext:   ".zip",
+			write: skeletonfs.WriteZip,
+			read: func(data []byte) (skeletonfs.ReadLinkFS, error) {
+				return skeletonfs.ReadZip(bytes.NewReader(data), int64(len(data)))
+			},
+		},
+	} {
+		t.Run(tc.ext, func(t *testing.T) {
+			var buf bytes.Buffer
+			require.NoError(t, tc.write(&buf, src))
+
+			archived, err := tc.read(buf.Bytes())
+			require.NoError(t, err)
+			skeletonfs.AssertTree(t, srcDir, archived, skeletonfs.CompareModes())
+
+			installDir := skeletonfs.SkeletonFS(archived).InstallTemp(t)
+			skeletonfs.AssertTree(t, installDir, src, skeletonfs.CompareModes())
+
+			name := filepath.Join(t.TempDir(), "skel"+tc.ext)
+			require.NoError(t, os.WriteFile(name, buf.Bytes(), 0600))
+			opened, err := skeletonfs.OpenArchive(name)
+			require.NoError(t, err)
+			skeletonfs.AssertTree(t, srcDir, opened, skeletonfs.CompareModes())
+		})
+	}
+}
+
+func TestTxtar(t *testing.T) {
+	archive := []byte(`a comment
+-- etc/app.conf --
+port=8080
+-- ./VERSION --
+v2
+-- empty --
+`)
+
+	fsys, err := skeletonfs.ReadTxtar(archive)
+	require.NoError(t, err)
+	installDir := skeletonfs.SkeletonFS(fsys).InstallTemp(t)
+	skeletonfs.AssertTree(t, installDir, skeletonfs.Tree{
+		"etc/app.conf": "port=8080\n",
+		"VERSION":      "v2\n",
+		"empty":        "",
+	}.FS())
+
+	var buf bytes.Buffer
+	require.NoError(t, skeletonfs.WriteTxtar(&buf, skeletonfs.DirFS(installDir), "a comment"))
+	assert.Equal(t, `a comment
+-- VERSION --
+v2
+-- empty --
+-- etc/app.conf --
+port=8080
+`, buf.String())
+
+	err = skeletonfs.WriteTxtar(&buf, archivedTree.FS(), "")
+	assert.ErrorContains(t, err, `"current": unsupported file type`)
+	err = skeletonfs.WriteTxtar(&buf, skeletonfs.Tree{"a": "x\n-- b --\n"}.FS(), "")
+	assert.ErrorContains(t, err, "file marker")
+	err = skeletonfs.WriteTxtar(&buf, skeletonfs.Tree{"a": "-- injected --\nfoo\n"}.FS(), "")
+	assert.ErrorContains(t, err, "file marker", "should check the first line")
+	err = skeletonfs.WriteTxtar(&buf, skeletonfs.Tree{"a": "x"}.FS(), "")
+	assert.ErrorContains(t, err, `"a": no final newline`)
+}
+
+func TestArchives_invalid(t *testing.T) {
+	_, err := skeletonfs.ReadTxtar([]byte("-- ../escape --\n"))
+	assert.ErrorContains(t, err, "invalid entry name")
+
+	_, err = skeletonfs.OpenArchive("skel.rar")
+	assert.ErrorContains(t, err, "unsupported extension")
+}
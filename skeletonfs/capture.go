@@ -0,0 +1,229 @@
+package skeletonfs
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing/fstest"
+
+	"github.com/jahkeup/testthings"
+)
+
+var update = flag.Bool("skeletonfs.update", false, "update skeletonfs golden fixtures with the captured directories")
+
+// Updating reports whether golden fixtures are being updated, with the
+// -skeletonfs.update flag.
+func Updating() bool {
+	return *update
+}
+
+// CaptureOption configures how directories are captured.
+type CaptureOption func(*captureOptions)
+
+type captureOptions struct {
+	exclude []string
+}
+
+// ExcludeGlobs leaves out the volatile paths matching any of the patterns, like
+// "*.log" or "run/*.pid", see IgnoreGlobs.
+func ExcludeGlobs(patterns ...string) CaptureOption {
+	return func(o *captureOptions) { o.exclude = append(o.exclude, patterns...) }
+}
+
+// Capture snapshots the directory's tree into memory, with its directories,
+// regular files, symlinks, modes, and modification times. The snapshot can be
+// installed with SkeletonFS or written out with the archive writers.
+func Capture(dir string, opts ...CaptureOption) (ReadLinkFS, error) {
+	var options captureOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	src := DirFS(dir)
+	mapFS := fstest.MapFS{}
+	err := fs.WalkDir(src, ".", func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if p == "." {
+			return nil
+		}
+		if matchGlobs(options.exclude, p) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		file := &fstest.MapFile{Mode: info.Mode().Type() | info.Mode().Perm(), ModTime: info.ModTime()}
+		switch {
+		case info.IsDir():
+		case info.Mode().IsRegular():
+			file.Data, err = fs.ReadFile(src, p)
+		case isSymlink(info.Mode()):
+			var target string
+			target, err = src.ReadLink(p)
+			file.Data = []byte(target)
+		default:
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		mapFS[p] = file
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("capture %q: %w", dir, err)
+	}
+	return linkMapFS{mapFS}, nil
+}
+
+// CaptureTo captures the directory and writes it to dest, replacing what's
+// there. Dest is written by its extension as an archive (see OpenArchive), or
+// otherwise as a directory tree, like in testdata. Txtar archives only carry
+// regular files and their contents, so other trees aren't written as txtar
+// (see WriteTxtar).
+func CaptureTo(dir, dest string, opts ...CaptureOption) error {
+	fsys, err := Capture(dir, opts...)
+	if err != nil {
+		return err
+	}
+
+	var write func(io.Writer, fs.FS) error
+	switch {
+	case strings.HasSuffix(dest, ".txtar"):
+		write = func(w io.Writer, fsys fs.FS) error { return WriteTxtar(w, fsys, "") }
+	case strings.HasSuffix(dest, ".tar"):
+		write = WriteTar
+	case strings.HasSuffix(dest, ".tar.gz"), strings.HasSuffix(dest, ".tgz"):
+		write = WriteTarGz
+	case strings.HasSuffix(dest, ".zip"):
+		write = WriteZip
+	default:
+		return writeTree(dest, fsys)
+	}
+
+	var buf bytes.Buffer
+	if err := write(&buf, fsys); err != nil {
+		return fmt.Errorf("capture %q: %w", dir, err)
+	}
+	return os.WriteFile(dest, buf.Bytes(), 0644)
+}
+
+// writeTree replaces the dest directory with the tree, keeping its exact
+// modes.
+func writeTree(dest string, fsys ReadLinkFS) error {
+	if info, err := os.Lstat(dest); err == nil {
+		if !info.IsDir() {
+			return fmt.Errorf("capture: %q is not a directory", dest)
+		}
+		if err := os.RemoveAll(dest); err != nil {
+			return fmt.Errorf("capture: %w", err)
+		}
+	}
+	if err := os.MkdirAll(dest, 0755); err != nil {
+		return fmt.Errorf("capture: %w", err)
+	}
+
+	var dirs []string
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil || p == "." {
+			return walkErr
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+
+		target := filepath.Join(dest, filepath.FromSlash(p))
+		switch {
+		case info.IsDir():
+			// written with their mode after their contents, which the mode
+			// might not allow writing.
+			dirs = append(dirs, p)
+			return os.Mkdir(target, 0700)
+		case isSymlink(info.Mode()):
+			link, err := fsys.ReadLink(p)
+			if err != nil {
+				return err
+			}
+			return os.Symlink(filepath.FromSlash(link), target)
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(target, data, 0600); err != nil {
+			return err
+		}
+		return os.Chmod(target, info.Mode().Perm())
+	})
+	if err != nil {
+		return fmt.Errorf("capture: %w", err)
+	}
+
+	for i := len(dirs) - 1; i >= 0; i-- {
+		info, err := fs.Stat(fsys, dirs[i])
+		if err != nil {
+			return fmt.Errorf("capture: %w", err)
+		}
+		if err := os.Chmod(filepath.Join(dest, filepath.FromSlash(dirs[i])), info.Mode().Perm()); err != nil {
+			return fmt.Errorf("capture: %w", err)
+		}
+	}
+	return nil
+}
+
+// AssertGolden compares the directory with the golden fixture, an archive or
+// directory tree, failing the test with the changes found. With the
+// -skeletonfs.update flag, the fixture is replaced with a capture of the
+// directory instead, failing when the fixture's format can't carry the tree
+// exactly. Modes aren't compared, as version control doesn't keep them.
+func AssertGolden(testingT testthings.Terminator, dir, fixture string, opts ...CaptureOption) {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	if Updating() {
+		if err := CaptureTo(dir, fixture, opts...); err != nil {
+			testingT.Fatal(fmt.Sprintf("golden update: %v", err))
+			return
+		}
+		if logger, ok := testingT.(testthings.Logger); ok {
+			logger.Log(fmt.Sprintf("golden update: wrote %s", fixture))
+		}
+		return
+	}
+
+	var options captureOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	var expected fs.FS
+	if info, err := os.Stat(fixture); err == nil && info.IsDir() {
+		expected = DirFS(fixture)
+	} else {
+		archived, err := OpenArchive(fixture)
+		if err != nil {
+			testingT.Fatal(fmt.Sprintf("golden fixture: %v (update with -skeletonfs.update)", err))
+			return
+		}
+		expected = archived
+	}
+
+	AssertTree(testingT, dir, expected, IgnoreGlobs(options.exclude...))
+}
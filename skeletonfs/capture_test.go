@@ -0,0 +1,87 @@
+package skeletonfs_test
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jahkeup/testthings/skeletonfs"
+)
+
+func TestCapture(t *testing.T) {
+	srcDir := skeletonfs.SkeletonFS(archivedTree.FS()).InstallTemp(t)
+	require.NoError(t, os.WriteFile(filepath.Join(srcDir, "var", "log", "app.log"), []byte("volatile"), 0600))
+
+	fsys, err := skeletonfs.Capture(srcDir, skeletonfs.ExcludeGlobs("*.log"))
+	require.NoError(t, err)
+	_, err = fsys.Lstat("var/log/app.log")
+	assert.ErrorIs(t, err, os.ErrNotExist, "should exclude volatile files")
+	skeletonfs.AssertTree(t, srcDir, fsys, skeletonfs.CompareModes(), skeletonfs.IgnoreGlobs("*.log"))
+
+	for _, dest := range []string{"tree", "skel.tar", "skel.tar.gz", "skel.zip"} {
+		dest := filepath.Join(t.TempDir(), dest)
+		require.NoError(t, skeletonfs.CaptureTo(srcDir, dest, skeletonfs.ExcludeGlobs("*.log")), dest)
+
+		skeletonfs.AssertGolden(t, srcDir, dest, skeletonfs.ExcludeGlobs("*.log"))
+		if filepath.Ext(dest) == "" {
+			skeletonfs.AssertTree(t, dest, fsys, skeletonfs.CompareModes())
+		}
+	}
+
+	err = skeletonfs.CaptureTo(srcDir, filepath.Join(t.TempDir(), "skel.txtar"))
+	assert.ErrorContains(t, err, "unsupported file type", "should not drop symlinks")
+}
+
+func TestAssertGolden(t *testing.T) {
+	dir := skeletonfs.SkeletonFS(skeletonfs.Tree{
+		"etc/app.conf":        "port=8080\n",
+		"releases/v2/VERSION": "v2\n",
+		"run/app.pid":         "123",
+	}.FS()).InstallTemp(t)
+	skeletonfs.AssertGolden(t, dir, filepath.Join("testdata", "golden.txtar"), skeletonfs.ExcludeGlobs("run"))
+
+	f := &fatals{}
+	skeletonfs.AssertGolden(f, dir, filepath.Join("testdata", "golden.txtar"))
+	require.Len(t, f.msgs, 1)
+	assert.Contains(t, f.msgs[0], "run/app.pid: added file")
+
+	f = &fatals{}
+	skeletonfs.AssertGolden(f, dir, filepath.Join(t.TempDir(), "missing.txtar"))
+	require.Len(t, f.msgs, 1)
+	assert.Contains(t, f.msgs[0], "-skeletonfs.update")
+}
+
+func TestAssertGolden_update(t *testing.T) {
+	require.NoError(t, flag.Set("skeletonfs.update", "true"))
+	t.Cleanup(func() { flag.Set("skeletonfs.update", "false") })
+	require.True(t, skeletonfs.Updating())
+
+	dir := skeletonfs.SkeletonFS(skeletonfs.Tree{"a.txt": "a"}.FS()).InstallTemp(t)
+	fixture := filepath.Join(t.TempDir(), "golden")
+	require.NoError(t, os.MkdirAll(filepath.Join(fixture, "stale"), 0755))
+
+	skeletonfs.AssertGolden(t, dir, fixture)
+	skeletonfs.AssertTree(t, fixture, skeletonfs.Tree{"a.txt": "a"}.FS())
+}
+
+func TestAssertGolden_updateTxtar(t *testing.T) {
+	fixture := filepath.Join(t.TempDir(), "golden.txtar")
+	dir := skeletonfs.SkeletonFS(skeletonfs.Tree{"a.txt": "a\n"}.FS()).InstallTemp(t)
+	noNewline := skeletonfs.SkeletonFS(skeletonfs.Tree{"a.txt": "a"}.FS()).InstallTemp(t)
+
+	require.NoError(t, flag.Set("skeletonfs.update", "true"))
+	t.Cleanup(func() { flag.Set("skeletonfs.update", "false") })
+	skeletonfs.AssertGolden(t, dir, fixture)
+
+	f := &fatals{}
+	skeletonfs.AssertGolden(f, noNewline, fixture)
+	require.Len(t, f.msgs, 1)
+	assert.Contains(t, f.msgs[0], `"a.txt": no final newline`)
+
+	require.NoError(t, flag.Set("skeletonfs.update", "false"))
+	skeletonfs.AssertGolden(t, dir, fixture)
+}
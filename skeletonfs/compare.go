@@ -0,0 +1,295 @@
+package skeletonfs
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/jahkeup/testthings"
+)
+
+// ChangeKind is the kind of difference found between trees.
+type ChangeKind int
+
+const (
+	// Added is a path only in the actual tree.
+	Added ChangeKind = iota
+	// Removed is a path only in the expected tree.
+	Removed
+	// Modified is a file with different content.
+	Modified
+	// ModeChanged is a path with different permissions.
+	ModeChanged
+	// TypeChanged is a path that is a different type of node, like a file
+	// that became a directory.
+	TypeChanged
+	// TargetChanged is a symlink with a different target.
+	TargetChanged
+)
+
+func (k ChangeKind) String() string {
+	switch k {
+	case Added:
+		return "added"
+	case Removed:
+		return "removed"
+	case Modified:
+		return "modified"
+	case ModeChanged:
+		return "mode changed"
+	case TypeChanged:
+		return "type changed"
+	case TargetChanged:
+		return "target changed"
+	}
+	return fmt.Sprintf("ChangeKind(%d)", int(k))
+}
+
+// Change is a difference at a path between the expected and actual trees.
+// Expected and Actual describe the path in each tree, as relevant to the kind
+// of change. Diff has the line differences of modified text files.
+type Change struct {
+	Path     string
+	Kind     ChangeKind
+	Expected string
+	Actual   string
+	Diff     string
+}
+
+func (c Change) String() string {
+	switch c.Kind {
+	case Added:
+		return fmt.Sprintf("%s: added %s", c.Path, c.Actual)
+	case Removed:
+		return fmt.Sprintf("%s: removed %s", c.Path, c.Expected)
+	case Modified:
+		if c.Diff != "" {
+			return fmt.Sprintf("%s: modified\n%s", c.Path, c.Diff)
+		}
+		return fmt.Sprintf("%s: modified, expected %s, got %s", c.Path, c.Expected, c.Actual)
+	}
+	return fmt.Sprintf("%s: %s, expected %s, got %s", c.Path, c.Kind, c.Expected, c.Actual)
+}
+
+// CompareOption configures how trees are compared.
+type CompareOption func(*compareOptions)
+
+type compareOptions struct {
+	ignore []string
+	modes  bool
+}
+
+// IgnoreGlobs ignores the paths matching any of the path.Match patterns, like
+// "*.log" or "run/*.lock". Patterns without a slash match base names anywhere
+// in the tree, others match the whole path. Ignored directories are ignored
+// with their contents.
+func IgnoreGlobs(patterns ...string) CompareOption {
+	return func(o *compareOptions) { o.ignore = append(o.ignore, patterns...) }
+}
+
+// CompareModes also compares the permission bits. They're not compared by
+// default, as installs raise them to MinimumFilePerm and MinimumDirPerm.
+func CompareModes() CompareOption {
+	return func(o *compareOptions) { o.modes = true }
+}
+
+func (o compareOptions) ignored(name string) bool {
+	return matchGlobs(o.ignore, name)
+}
+
+// matchGlobs reports whether the slash separated path matches any of the
+// patterns, see IgnoreGlobs.
+func matchGlobs(patterns []string, name string) bool {
+	for _, pattern := range patterns {
+		subject := name
+		if !strings.Contains(pattern, "/") {
+			subject = path.Base(name)
+		}
+		if matched, _ := path.Match(pattern, subject); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Compare the directory's tree with the expected tree, returning the changes
+// in path order. Symlinks are compared by target when the expected fs.FS is a
+// ReadLinkFS, and otherwise only by type.
+func Compare(dir string, expected fs.FS, opts ...CompareOption) ([]Change, error) {
+	var options compareOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	actual := DirFS(dir)
+	actualNodes, err := treeNodes(actual, options)
+	if err != nil {
+		return nil, fmt.Errorf("actual: %w", err)
+	}
+	expectedNodes, err := treeNodes(expected, options)
+	if err != nil {
+		return nil, fmt.Errorf("expected: %w", err)
+	}
+
+	paths := make([]string, 0, len(expectedNodes)+len(actualNodes))
+	for p := range expectedNodes {
+		paths = append(paths, p)
+	}
+	for p := range actualNodes {
+		if _, ok := expectedNodes[p]; !ok {
+			paths = append(paths, p)
+		}
+	}
+	sort.Strings(paths)
+
+	var changes []Change
+	for _, p := range paths {
+		e, inExpected := expectedNodes[p]
+		a, inActual := actualNodes[p]
+		switch {
+		case !inActual:
+			changes = append(changes, Change{Path: p, Kind: Removed, Expected: describeType(e)})
+		case !inExpected:
+			changes = append(changes, Change{Path: p, Kind: Added, Actual: describeType(a)})
+		default:
+			change, err := compareNode(p, expected, actual, e, a, options)
+			if err != nil {
+				return nil, err
+			}
+			changes = append(changes, change...)
+		}
+	}
+	return changes, nil
+}
+
+// AssertTree compares the directory's tree with the expected tree, failing the
+// test with the changes found.
+func AssertTree(testingT testthings.Terminator, dir string, expected fs.FS, opts ...CompareOption) {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	changes, err := Compare(dir, expected, opts...)
+	if err != nil {
+		testingT.Fatal(fmt.Sprintf("tree eq! but: %v", err))
+		return
+	}
+	if len(changes) == 0 {
+		return
+	}
+
+	lines := make([]string, 0, len(changes))
+	for _, c := range changes {
+		lines = append(lines, "\t"+strings.ReplaceAll(c.String(), "\n", "\n\t\t"))
+	}
+	testingT.Fatal(fmt.Sprintf("tree eq! but:\n%s", strings.Join(lines, "\n")))
+}
+
+// treeNodes collects the modes of the paths in the tree, except the root and
+// ignored paths.
+func treeNodes(fsys fs.FS, options compareOptions) (map[string]fs.FileMode, error) {
+	nodes := map[string]fs.FileMode{}
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if p == "." {
+			return nil
+		}
+		if options.ignored(p) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		nodes[p] = info.Mode()
+		return nil
+	})
+	return nodes, err
+}
+
+func describeType(mode fs.FileMode) string {
+	switch {
+	case mode.IsDir():
+		return "directory"
+	case mode.IsRegular():
+		return "file"
+	case isSymlink(mode):
+		return "symlink"
+	}
+	return mode.Type().String()
+}
+
+// compareNode compares the path that is in both trees.
+func compareNode(p string, expectedFS, actualFS fs.FS, expected, actual fs.FileMode, options compareOptions) ([]Change, error) {
+	if expected.Type() != actual.Type() {
+		return []Change{{Path: p, Kind: TypeChanged, Expected: describeType(expected), Actual: describeType(actual)}}, nil
+	}
+
+	var changes []Change
+	if options.modes && !isSymlink(expected) && expected.Perm() != actual.Perm() {
+		changes = append(changes, Change{Path: p, Kind: ModeChanged, Expected: expected.Perm().String(), Actual: actual.Perm().String()})
+	}
+
+	switch {
+	case expected.IsRegular():
+		e, err := fs.ReadFile(expectedFS, p)
+		if err != nil {
+			return nil, fmt.Errorf("expected: %w", err)
+		}
+		a, err := fs.ReadFile(actualFS, p)
+		if err != nil {
+			return nil, fmt.Errorf("actual: %w", err)
+		}
+		if !bytes.Equal(e, a) {
+			changes = append(changes, contentChange(p, e, a))
+		}
+
+	case isSymlink(expected):
+		expectedLinks, ok := expectedFS.(ReadLinkFS)
+		if !ok {
+			break
+		}
+		e, err := expectedLinks.ReadLink(p)
+		if err != nil {
+			return nil, fmt.Errorf("expected: %w", err)
+		}
+		a, err := actualFS.(ReadLinkFS).ReadLink(p)
+		if err != nil {
+			return nil, fmt.Errorf("actual: %w", err)
+		}
+		if e != a {
+			changes = append(changes, Change{Path: p, Kind: TargetChanged, Expected: fmt.Sprintf("%q", e), Actual: fmt.Sprintf("%q", a)})
+		}
+	}
+	return changes, nil
+}
+
+// contentChange describes the modified file with a diff of text content, or
+// with hashes of binary content.
+func contentChange(p string, expected, actual []byte) Change {
+	if isText(expected) && isText(actual) {
+		return Change{Path: p, Kind: Modified, Diff: lineDiff(string(expected), string(actual))}
+	}
+	return Change{
+		Path:     p,
+		Kind:     Modified,
+		Expected: fmt.Sprintf("sha256:%x (%d bytes)", sha256.Sum256(expected), len(expected)),
+		Actual:   fmt.Sprintf("sha256:%x (%d bytes)", sha256.Sum256(actual), len(actual)),
+	}
+}
+
+func isText(data []byte) bool {
+	return utf8.Valid(data) && !bytes.ContainsRune(data, 0)
+}
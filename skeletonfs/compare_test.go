@@ -0,0 +1,121 @@
+package skeletonfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jahkeup/testthings/skeletonfs"
+)
+
+type fatals struct {
+	msgs []string
+}
+
+func (f *fatals) Fatal(args ...any) {
+	f.msgs = append(f.msgs, args[0].(string))
+}
+
+func TestCompare(t *testing.T) {
+	expected := fstest.MapFS{
+		"etc/app.conf": &fstest.MapFile{Data: []byte("a\nb\nc\nd\ne\nf\ng\n"), Mode: 0644},
+		"bin/tool":     &fstest.MapFile{Data: []byte{0, 1, 2}, Mode: 0750},
+		"var/gone":     &fstest.MapFile{Data: []byte("gone")},
+		"var/dir/same": &fstest.MapFile{Data: []byte("same")},
+	}
+
+	installDir := t.TempDir()
+	require.NoError(t, skeletonfs.SkeletonFS(expected).Install(installDir))
+	changes, err := skeletonfs.Compare(installDir, expected)
+	require.NoError(t, err)
+	assert.Empty(t, changes, "should match the installed skeleton")
+
+	require.NoError(t, os.WriteFile(filepath.Join(installDir, "etc", "app.conf"), []byte("a\nb\nc\nD\ne\nf\ng\n"), 0640))
+	require.NoError(t, os.Chmod(filepath.Join(installDir, "bin", "tool"), 0700))
+	require.NoError(t, os.WriteFile(filepath.Join(installDir, "bin", "tool"), []byte{0, 1}, 0700))
+	require.NoError(t, os.Remove(filepath.Join(installDir, "var", "gone")))
+	require.NoError(t, os.WriteFile(filepath.Join(installDir, "var", "new"), []byte("new"), 0640))
+	require.NoError(t, os.WriteFile(filepath.Join(installDir, "var", "app.log"), []byte("log"), 0640))
+	require.NoError(t, os.RemoveAll(filepath.Join(installDir, "var", "dir")))
+	require.NoError(t, os.WriteFile(filepath.Join(installDir, "var", "dir"), []byte("file"), 0640))
+
+	changes, err = skeletonfs.Compare(installDir, expected, skeletonfs.IgnoreGlobs("*.log"), skeletonfs.CompareModes())
+	require.NoError(t, err)
+
+	var kinds []string
+	for _, c := range changes {
+		t.Log(c)
+		kinds = append(kinds, c.Path+" "+c.Kind.String())
+	}
+	assert.Equal(t, []string{
+		"bin mode changed",
+		"bin/tool mode changed",
+		"bin/tool modified",
+		"etc mode changed",
+		"etc/app.conf modified",
+		"var mode changed",
+		"var/dir type changed",
+		"var/dir/same removed",
+		"var/gone removed",
+		"var/new added",
+	}, kinds)
+
+	for _, c := range changes {
+		switch {
+		case c.Path == "etc/app.conf" && c.Kind == skeletonfs.Modified:
+			assert.Equal(t, "...\n b\n c\n-d\n+D\n e\n f\n...", c.Diff)
+		case c.Path == "bin/tool" && c.Kind == skeletonfs.Modified:
+			assert.Empty(t, c.Diff, "should not diff binary files")
+			assert.Contains(t, c.Expected, "sha256:")
+			assert.Contains(t, c.Actual, "(2 bytes)")
+		}
+	}
+}
+
+func TestCompare_finalNewline(t *testing.T) {
+	expected := fstest.MapFS{"app.conf": &fstest.MapFile{Data: []byte("a")}}
+	installDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(installDir, "app.conf"), []byte("a\n"), 0640))
+
+	changes, err := skeletonfs.Compare(installDir, expected)
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, "app.conf: modified\n-a\n\\ No newline at end of file\n+a", changes[0].String())
+}
+
+func TestCompare_symlinks(t *testing.T) {
+	src := symlinkedSource(t, nil)
+	installDir := t.TempDir()
+	require.NoError(t, skeletonfs.SkeletonFS(skeletonfs.DirFS(src)).Install(installDir))
+
+	require.NoError(t, os.Remove(filepath.Join(installDir, "current")))
+	require.NoError(t, os.Symlink("releases", filepath.Join(installDir, "current")))
+
+	changes, err := skeletonfs.Compare(installDir, skeletonfs.DirFS(src))
+	require.NoError(t, err)
+	require.Len(t, changes, 1)
+	assert.Equal(t, skeletonfs.TargetChanged, changes[0].Kind)
+	assert.Equal(t, `current: target changed, expected "releases/v2", got "releases"`, changes[0].String())
+}
+
+func TestAssertTree(t *testing.T) {
+	expected := fstest.MapFS{
+		"run/app.lock": &fstest.MapFile{Data: []byte("1\n"), Mode: 0644},
+		"app.conf":     &fstest.MapFile{Data: []byte("conf")},
+	}
+	installDir := t.TempDir()
+	require.NoError(t, skeletonfs.SkeletonFS(expected).Install(installDir))
+	require.NoError(t, os.WriteFile(filepath.Join(installDir, "run", "app.lock"), []byte("2\n"), 0640))
+
+	skeletonfs.AssertTree(t, installDir, expected, skeletonfs.IgnoreGlobs("run/*.lock"))
+
+	f := &fatals{}
+	skeletonfs.AssertTree(f, installDir, expected)
+	require.Len(t, f.msgs, 1)
+	assert.True(t, strings.HasPrefix(f.msgs[0], "tree eq! but:\n\trun/app.lock: modified\n\t\t-1\n\t\t+2"), f.msgs[0])
+}
@@ -0,0 +1,243 @@
+package skeletonfs
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"time"
+)
+
+// ConflictPolicy decides what happens when a skeleton's file or symlink is
+// installed over an existing one.
+type ConflictPolicy int
+
+const (
+	// ConflictFail fails the install.
+	ConflictFail ConflictPolicy = iota
+	// ConflictSkip keeps the existing file.
+	ConflictSkip
+	// ConflictOverwrite replaces the existing file.
+	ConflictOverwrite
+	// ConflictOverwriteChanged replaces the existing file only when its
+	// content (or symlink target) differs.
+	ConflictOverwriteChanged
+	// ConflictMerge replaces existing files with the result of the MergeFunc
+	// given by WithMerge. Symlinks are overwritten when changed.
+	ConflictMerge
+)
+
+func (p ConflictPolicy) String() string {
+	switch p {
+	case ConflictFail:
+		return "fail"
+	case ConflictSkip:
+		return "skip"
+	case ConflictOverwrite:
+		return "overwrite"
+	case ConflictOverwriteChanged:
+		return "overwrite-changed"
+	case ConflictMerge:
+		return "merge"
+	}
+	return fmt.Sprintf("ConflictPolicy(%d)", int(p))
+}
+
+// MergeFunc merges the skeleton's content for the path into the existing
+// content, returning what is installed.
+type MergeFunc func(path string, existing, skeleton []byte) ([]byte, error)
+
+// Action is what the install did for a path.
+type Action int
+
+const (
+	// ActionCreated is a path that didn't exist before.
+	ActionCreated Action = iota
+	// ActionSkipped is an existing path that was kept by ConflictSkip.
+	ActionSkipped
+	// ActionOverwritten is an existing path that was replaced.
+	ActionOverwritten
+	// ActionUnchanged is an existing path that already had the skeleton's
+	// content, or an existing directory.
+	ActionUnchanged
+	// ActionMerged is an existing file replaced with the MergeFunc's result.
+	ActionMerged
+)
+
+func (a Action) String() string {
+	switch a {
+	case ActionCreated:
+		return "created"
+	case ActionSkipped:
+		return "skipped"
+	case ActionOverwritten:
+		return "overwritten"
+	case ActionUnchanged:
+		return "unchanged"
+	case ActionMerged:
+		return "merged"
+	}
+	return fmt.Sprintf("Action(%d)", int(a))
+}
+
+// InstallEntry records the Action taken for a path of the skeleton. Source is
+// the path in the skeleton, which differs from the installed Path when the
+// name was rendered or renamed. Perm and ModTime are the installed path's
+// resulting metadata.
+type InstallEntry struct {
+	Path    string
+	Source  string
+	Type    fs.FileMode
+	Action  Action
+	Perm    fs.FileMode
+	ModTime time.Time
+
+	source fs.FileInfo
+}
+
+func (e InstallEntry) String() string {
+	return fmt.Sprintf("%s %q (%v)", e.Action, e.Path, e.Type)
+}
+
+// InstallReport records what the install did, in the order it was done.
+type InstallReport struct {
+	Entries []InstallEntry
+}
+
+// Action returns the action taken for the path, if it was installed.
+func (r *InstallReport) Action(path string) (Action, bool) {
+	for _, e := range r.Entries {
+		if e.Path == path {
+			return e.Action, true
+		}
+	}
+	return 0, false
+}
+
+func (r *InstallReport) record(path, source string, info fs.FileInfo, action Action) {
+	r.Entries = append(r.Entries, InstallEntry{Path: path, Source: source, Type: info.Mode().Type(), Action: action, source: info})
+}
+
+// writeFile installs the data at the install path, resolving conflicts with an
+// existing file by the options' policy.
+func writeFile(name, installPath string, data []byte, perm fs.FileMode, opts options) (Action, error) {
+	existing, err := os.Lstat(installPath)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && opts.conflicts == ConflictFail) {
+		// conflicts fail when creating the file.
+		return ActionCreated, createFile(installPath, data, perm)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("install file: %w", err)
+	}
+
+	switch opts.conflicts {
+	case ConflictSkip:
+		return ActionSkipped, nil
+
+	case ConflictOverwrite:
+		return ActionOverwritten, replaceFile(installPath, existing, data, perm)
+
+	case ConflictOverwriteChanged:
+		if existing.Mode().IsRegular() {
+			current, err := os.ReadFile(installPath)
+			if err != nil {
+				return 0, fmt.Errorf("read existing: %w", err)
+			}
+			if bytes.Equal(current, data) {
+				return ActionUnchanged, nil
+			}
+		}
+		return ActionOverwritten, replaceFile(installPath, existing, data, perm)
+
+	case ConflictMerge:
+		if opts.merge == nil {
+			return 0, errors.New("merge: no MergeFunc given")
+		}
+		if !existing.Mode().IsRegular() {
+			return 0, fmt.Errorf("merge: cannot merge into %v", existing.Mode().Type())
+		}
+		current, err := os.ReadFile(installPath)
+		if err != nil {
+			return 0, fmt.Errorf("read existing: %w", err)
+		}
+		merged, err := opts.merge(name, current, data)
+		if err != nil {
+			return 0, fmt.Errorf("merge: %w", err)
+		}
+		return ActionMerged, replaceFile(installPath, existing, merged, perm)
+	}
+
+	return 0, fmt.Errorf("unknown conflict policy: %v", opts.conflicts)
+}
+
+// writeSymlink installs the symlink at the install path, resolving conflicts
+// with an existing file by the options' policy.
+func writeSymlink(installPath, target string, opts options) (Action, error) {
+	existing, err := os.Lstat(installPath)
+	if errors.Is(err, fs.ErrNotExist) || (err == nil && opts.conflicts == ConflictFail) {
+		return ActionCreated, createSymlink(installPath, target)
+	}
+	if err != nil {
+		return 0, fmt.Errorf("install symlink: %w", err)
+	}
+
+	switch opts.conflicts {
+	case ConflictSkip:
+		return ActionSkipped, nil
+	case ConflictOverwriteChanged, ConflictMerge:
+		if isSymlink(existing.Mode()) {
+			current, err := os.Readlink(installPath)
+			if err != nil {
+				return 0, fmt.Errorf("read existing: %w", err)
+			}
+			if current == target {
+				return ActionUnchanged, nil
+			}
+		}
+	}
+
+	if err := removeExisting(installPath, existing); err != nil {
+		return 0, err
+	}
+	return ActionOverwritten, createSymlink(installPath, target)
+}
+
+func createFile(installPath string, data []byte, perm fs.FileMode) error {
+	installF, err := os.OpenFile(installPath, os.O_WRONLY|os.O_EXCL|os.O_CREATE, perm)
+	if err != nil {
+		return fmt.Errorf("install file: %w", err)
+	}
+	defer installF.Close()
+
+	if _, err := installF.Write(data); err != nil {
+		return err
+	}
+	return installF.Close()
+}
+
+func createSymlink(installPath, target string) error {
+	if err := os.Symlink(target, installPath); err != nil {
+		return fmt.Errorf("install symlink: %w", err)
+	}
+	return nil
+}
+
+// replaceFile replaces the existing file, which may be read-only, with a new
+// one.
+func replaceFile(installPath string, existing fs.FileInfo, data []byte, perm fs.FileMode) error {
+	if err := removeExisting(installPath, existing); err != nil {
+		return err
+	}
+	return createFile(installPath, data, perm)
+}
+
+func removeExisting(installPath string, existing fs.FileInfo) error {
+	if existing.IsDir() {
+		return errors.New("cannot overwrite existing directory")
+	}
+	if err := os.Remove(installPath); err != nil {
+		return fmt.Errorf("remove existing: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package skeletonfs_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jahkeup/testthings/skeletonfs"
+	"github.com/jahkeup/testthings/testerr"
+)
+
+var (
+	baseSkel = fstest.MapFS{
+		"etc/app.conf":  &fstest.MapFile{Data: []byte("base"), Mode: 0444},
+		"etc/same.conf": &fstest.MapFile{Data: []byte("same")},
+	}
+	overlaySkel = fstest.MapFS{
+		"etc/app.conf":  &fstest.MapFile{Data: []byte("overlay")},
+		"etc/same.conf": &fstest.MapFile{Data: []byte("same")},
+		"etc/new.conf":  &fstest.MapFile{Data: []byte("new")},
+	}
+)
+
+func TestSkeletonFS_conflicts(t *testing.T) {
+	for _, tc := range []struct {
+		opts     []skeletonfs.Option
+		expected map[string]string
+		actions  map[string]skeletonfs.Action
+	}{
+		{
+			opts:     []skeletonfs.Option{skeletonfs.WithConflictPolicy(skeletonfs.ConflictSkip)},
+			expected: map[string]string{"etc/app.conf": "base", "etc/new.conf": "new"},
+			actions: map[string]skeletonfs.Action{
+				"etc":           skeletonfs.ActionUnchanged,
+				"etc/app.conf":  skeletonfs.ActionSkipped,
+				"etc/same.conf": skeletonfs.ActionSkipped,
+				"etc/new.conf":  skeletonfs.ActionCreated,
+			},
+		},
+		{
+			opts:     []skeletonfs.Option{skeletonfs.WithConflictPolicy(skeletonfs.ConflictOverwrite)},
+			expected: map[string]string{"etc/app.conf": "overlay", "etc/new.conf": "new"},
+			actions: map[string]skeletonfs.Action{
+				"etc/app.conf":  skeletonfs.ActionOverwritten,
+				"etc/same.conf": skeletonfs.ActionOverwritten,
+			},
+		},
+		{
+			opts:     []skeletonfs.Option{skeletonfs.WithConflictPolicy(skeletonfs.ConflictOverwriteChanged)},
+			expected: map[string]string{"etc/app.conf": "overlay"},
+			actions: map[string]skeletonfs.Action{
+				"etc/app.conf":  skeletonfs.ActionOverwritten,
+				"etc/same.conf": skeletonfs.ActionUnchanged,
+			},
+		},
+		{
+			opts: []skeletonfs.Option{skeletonfs.WithMerge(func(path string, existing, skeleton []byte) ([]byte, error) {
+				return append(append(existing, '+'), skeleton...), nil
+			})},
+			expected: map[string]string{"etc/app.conf": "base+overlay", "etc/same.conf": "same+same"},
+			actions: map[string]skeletonfs.Action{
+				"etc/app.conf": skeletonfs.ActionMerged,
+				"etc/new.conf": skeletonfs.ActionCreated,
+			},
+		},
+	} {
+		installDir := t.TempDir()
+		require.NoError(t, skeletonfs.SkeletonFS(baseSkel).Install(installDir))
+
+		report, err := skeletonfs.SkeletonFS(overlaySkel, tc.opts...).InstallWithReport(installDir)
+		require.NoError(t, err)
+		t.Log(report.Entries)
+
+		for path, expected := range tc.expected {
+			data, err := os.ReadFile(filepath.Join(installDir, path))
+			if assert.NoError(t, err) {
+				assert.Equalf(t, expected, string(data), "for %q", path)
+			}
+		}
+		for path, expected := range tc.actions {
+			action, ok := report.Action(path)
+			assert.Truef(t, ok, "should report %q", path)
+			assert.Equalf(t, expected, action, "for %q", path)
+		}
+	}
+}
+
+func TestSkeletonFS_conflictFail(t *testing.T) {
+	installDir := t.TempDir()
+	require.NoError(t, skeletonfs.SkeletonFS(baseSkel).Install(installDir))
+
+	report, err := skeletonfs.SkeletonFS(overlaySkel).InstallWithReport(installDir)
+	assert.ErrorIs(t, err, fs.ErrExist)
+	_, ok := report.Action("etc")
+	assert.True(t, ok, "should report up to the conflict")
+}
+
+func TestSkeletonFS_mergeError(t *testing.T) {
+	installDir := t.TempDir()
+	require.NoError(t, skeletonfs.SkeletonFS(baseSkel).Install(installDir))
+
+	err := skeletonfs.SkeletonFS(overlaySkel, skeletonfs.WithMerge(func(string, []byte, []byte) ([]byte, error) {
+		return nil, testerr.Expected
+	})).Install(installDir)
+	var installErr skeletonfs.SkeletonInstallError
+	require.ErrorAs(t, err, &installErr)
+	assert.ErrorIs(t, err, testerr.Expected)
+	assert.Equal(t, "etc/app.conf", installErr.Path)
+}
+
+func TestSkeletonFS_symlinkConflicts(t *testing.T) {
+	src := symlinkedSource(t, nil)
+	installDir := t.TempDir()
+	require.NoError(t, os.Symlink("elsewhere", filepath.Join(installDir, "current")))
+
+	report, err := skeletonfs.SkeletonFS(skeletonfs.DirFS(src),
+		skeletonfs.WithConflictPolicy(skeletonfs.ConflictOverwriteChanged)).InstallWithReport(installDir)
+	require.NoError(t, err)
+	action, _ := report.Action("current")
+	assert.Equal(t, skeletonfs.ActionOverwritten, action)
+
+	report, err = skeletonfs.SkeletonFS(skeletonfs.DirFS(src),
+		skeletonfs.WithConflictPolicy(skeletonfs.ConflictOverwriteChanged)).InstallWithReport(installDir)
+	require.NoError(t, err)
+	action, _ = report.Action("current")
+	assert.Equal(t, skeletonfs.ActionUnchanged, action)
+}
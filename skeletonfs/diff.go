@@ -0,0 +1,102 @@
+package skeletonfs
+
+import (
+	"fmt"
+	"strings"
+)
+
+const (
+	// diffContext is the number of unchanged lines shown around changes.
+	diffContext = 2
+	// maxDiffCells limits the size of the lines compared, the diff is
+	// quadratic.
+	maxDiffCells = 4_000_000
+	// noNewline marks the last line of texts without a final newline, as
+	// diff does.
+	noNewline = "\n\\ No newline at end of file"
+)
+
+// lineDiff renders the line differences between the texts, with unchanged
+// lines prefixed by " ", removed lines by "-", and added lines by "+". Long
+// runs of unchanged lines are elided. A missing final newline is marked after
+// the line, so it shows as a difference.
+func lineDiff(expected, actual string) string {
+	a := splitLines(expected)
+	b := splitLines(actual)
+	if len(a)*len(b) > maxDiffCells {
+		return fmt.Sprintf("(%d lines expected, %d lines actual, too large to diff)", len(a), len(b))
+	}
+
+	// longest common subsequence lengths of the suffixes.
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	type line struct {
+		op   byte
+		text string
+	}
+	var lines []line
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			lines = append(lines, line{' ', a[i]})
+			i++
+			j++
+		case i < len(a) && (j == len(b) || lcs[i+1][j] >= lcs[i][j+1]):
+			lines = append(lines, line{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, line{'+', b[j]})
+			j++
+		}
+	}
+
+	// keep unchanged lines only near changes.
+	keep := make([]bool, len(lines))
+	for n, l := range lines {
+		if l.op == ' ' {
+			continue
+		}
+		for k := max(0, n-diffContext); k <= min(len(lines)-1, n+diffContext); k++ {
+			keep[k] = true
+		}
+	}
+
+	var out []string
+	elided := false
+	for n, l := range lines {
+		if !keep[n] {
+			if !elided {
+				out = append(out, "...")
+				elided = true
+			}
+			continue
+		}
+		elided = false
+		out = append(out, string(l.op)+l.text)
+	}
+	return strings.Join(out, "\n")
+}
+
+func splitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	lines := strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+	if !strings.HasSuffix(text, "\n") {
+		lines[len(lines)-1] += noNewline
+	}
+	return lines
+}
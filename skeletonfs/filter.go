@@ -0,0 +1,154 @@
+package skeletonfs
+
+import (
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+)
+
+type filterOptions struct {
+	prefix  string
+	root    string
+	include []string
+	exclude []string
+	rename  func(name string) string
+}
+
+// WithSubtree installs only the skeleton's directory at the prefix, with its
+// contents at the top of the install dir.
+func WithSubtree(prefix string) Option {
+	return WithSubtreeAs(prefix, ".")
+}
+
+// WithSubtreeAs installs only the skeleton's directory at the prefix, renamed
+// to root within the install dir.
+func WithSubtreeAs(prefix, root string) Option {
+	return func(o *options) {
+		o.filters.prefix = path.Clean(prefix)
+		o.filters.root = path.Clean(root)
+	}
+}
+
+// WithInclude installs only the paths matching any of the patterns, see
+// IgnoreGlobs, along with the directories leading to them. Directories that
+// match are installed with everything in them. Patterns match the paths within
+// the subtree, if any.
+func WithInclude(patterns ...string) Option {
+	return func(o *options) { o.filters.include = append(o.filters.include, patterns...) }
+}
+
+// WithExclude leaves out the paths matching any of the patterns, see
+// IgnoreGlobs. Excluded directories are left out with everything in them.
+// Patterns match the paths within the subtree, if any.
+func WithExclude(patterns ...string) Option {
+	return func(o *options) { o.filters.exclude = append(o.filters.exclude, patterns...) }
+}
+
+// WithRename installs the skeleton's paths at the names returned by the
+// function, which is given the slash separated path within the subtree (if
+// any) after any templating. Returning an empty name leaves the path out.
+// Missing parent directories of renamed paths are created.
+func WithRename(rename func(name string) string) Option {
+	return func(o *options) { o.filters.rename = rename }
+}
+
+// walkRoot is where the install walks the skeleton from.
+func (f filterOptions) walkRoot() string {
+	if f.prefix == "" {
+		return "."
+	}
+	return f.prefix
+}
+
+// relative returns the skeleton's path within the subtree.
+func (f filterOptions) relative(p string) string {
+	root := f.walkRoot()
+	if p == root {
+		return "."
+	}
+	if root == "." {
+		return p
+	}
+	return strings.TrimPrefix(p, root+"/")
+}
+
+// selectPaths finds the paths within the subtree to install, when filtering
+// includes only some of them.
+func (f filterOptions) selectPaths(skelFS fs.FS) (map[string]bool, error) {
+	if len(f.include) == 0 {
+		return nil, nil
+	}
+
+	selected := map[string]bool{".": true}
+	includedDirs := map[string]bool{}
+	err := fs.WalkDir(skelFS, f.walkRoot(), func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		rel := f.relative(p)
+		if rel == "." {
+			return nil
+		}
+		if matchGlobs(f.exclude, rel) {
+			if d.IsDir() {
+				return fs.SkipDir
+			}
+			return nil
+		}
+
+		if !includedDirs[path.Dir(rel)] && !matchGlobs(f.include, rel) {
+			return nil
+		}
+		if d.IsDir() {
+			includedDirs[rel] = true
+		}
+		for sel := rel; sel != "."; sel = path.Dir(sel) {
+			selected[sel] = true
+		}
+		return nil
+	})
+	return selected, err
+}
+
+// skipsDir reports whether the skeleton's directory is left out with
+// everything in it, by exclusion or by not leading to any included paths.
+func (o options) skipsDir(p string) bool {
+	rel := o.filters.relative(p)
+	if rel == "." {
+		return false
+	}
+	return matchGlobs(o.filters.exclude, rel) || (o.selected != nil && !o.selected[rel])
+}
+
+// installName maps the skeleton's path to its name in the install dir,
+// returning false when it's left out.
+func (o options) installName(p string, mode fs.FileMode) (string, bool, error) {
+	rel := o.filters.relative(p)
+	if rel != "." && matchGlobs(o.filters.exclude, rel) {
+		return "", false, nil
+	}
+	if o.selected != nil && !o.selected[rel] {
+		return "", false, nil
+	}
+
+	name, err := o.templates.installName(rel, mode)
+	if err != nil {
+		return "", false, err
+	}
+
+	if o.filters.rename != nil && name != "." {
+		name = o.filters.rename(name)
+		if name == "" {
+			return "", false, nil
+		}
+		if !fs.ValidPath(name) {
+			return "", false, fmt.Errorf("rename: invalid path %q", name)
+		}
+	}
+
+	if o.filters.root != "" {
+		name = path.Join(o.filters.root, name)
+	}
+	return name, true, nil
+}
@@ -0,0 +1,109 @@
+package skeletonfs_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jahkeup/testthings/skeletonfs"
+)
+
+var sharedSkel = skeletonfs.Tree{
+	"services/api/etc/api.conf":  "api",
+	"services/api/etc/debug.log": "log",
+	"services/api/bin/api":       "bin",
+	"services/api/VERSION":       "1",
+	"services/web/etc/web.conf":  "web",
+	"common/ca.pem":              "ca",
+}.FS()
+
+func TestSkeletonFS_filters(t *testing.T) {
+	for name, tc := range map[string]struct {
+		opts     []skeletonfs.Option
+		expected skeletonfs.Tree
+	}{
+		"subtree": {
+			opts: []skeletonfs.Option{skeletonfs.WithSubtree("services/api")},
+			expected: skeletonfs.Tree{
+				"etc/api.conf":  "api",
+				"etc/debug.log": "log",
+				"bin/api":       "bin",
+				"VERSION":       "1",
+			},
+		},
+		"subtree as": {
+			opts: []skeletonfs.Option{skeletonfs.WithSubtreeAs("services/web", "srv/web")},
+			expected: skeletonfs.Tree{
+				"srv/web/etc/web.conf": "web",
+			},
+		},
+		"include": {
+			opts: []skeletonfs.Option{skeletonfs.WithInclude("*.conf", "common")},
+			expected: skeletonfs.Tree{
+				"services/api/etc/api.conf": "api",
+				"services/web/etc/web.conf": "web",
+				"common/ca.pem":             "ca",
+			},
+		},
+		"exclude": {
+			opts: []skeletonfs.Option{
+				skeletonfs.WithSubtree("services"),
+				skeletonfs.WithExclude("*.log", "api/bin", "web"),
+			},
+			expected: skeletonfs.Tree{
+				"api/etc/api.conf": "api",
+				"api/VERSION":      "1",
+			},
+		},
+		"include and exclude": {
+			opts: []skeletonfs.Option{
+				skeletonfs.WithInclude("services/api/etc"),
+				skeletonfs.WithExclude("*.log"),
+			},
+			expected: skeletonfs.Tree{
+				"services/api/etc/api.conf": "api",
+			},
+		},
+		"rename": {
+			opts: []skeletonfs.Option{
+				skeletonfs.WithSubtreeAs("services/api", "opt"),
+				skeletonfs.WithRename(func(name string) string {
+					switch {
+					case strings.HasSuffix(name, ".log"):
+						return ""
+					case strings.HasPrefix(name, "etc/"):
+						return "config/" + strings.TrimPrefix(name, "etc/")
+					case name == "etc":
+						return ""
+					}
+					return name
+				}),
+			},
+			expected: skeletonfs.Tree{
+				"opt/config/api.conf": "api",
+				"opt/bin/api":         "bin",
+				"opt/VERSION":         "1",
+			},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			installDir := skeletonfs.SkeletonFS(sharedSkel, tc.opts...).InstallTemp(t)
+			skeletonfs.AssertTree(t, installDir, tc.expected.FS())
+		})
+	}
+}
+
+func TestSkeletonFS_filterErrors(t *testing.T) {
+	err := skeletonfs.SkeletonFS(sharedSkel, skeletonfs.WithSubtree("missing")).Install(t.TempDir())
+	var installErr skeletonfs.SkeletonInstallError
+	require.ErrorAs(t, err, &installErr)
+	assert.Equal(t, "missing", installErr.Path)
+
+	err = skeletonfs.SkeletonFS(sharedSkel, skeletonfs.WithSubtree("common/ca.pem")).Install(t.TempDir())
+	assert.ErrorContains(t, err, "not a directory")
+
+	err = skeletonfs.SkeletonFS(sharedSkel, skeletonfs.WithRename(func(string) string { return "../escape" })).Install(t.TempDir())
+	assert.ErrorContains(t, err, "invalid path")
+}
@@ -0,0 +1,155 @@
+package skeletonfs
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jahkeup/testthings"
+)
+
+// ModePolicy decides the permissions of installed files and directories.
+type ModePolicy int
+
+const (
+	// ModeMinimum raises the skeleton's modes to MinimumFilePerm and
+	// MinimumDirPerm, which the umask may then lower.
+	ModeMinimum ModePolicy = iota
+	// ModeMinimumChmod is ModeMinimum with the modes set after install,
+	// regardless of the umask.
+	ModeMinimumChmod
+	// ModeExact sets the skeleton's modes exactly after install, regardless
+	// of the umask. Directories are set after everything in them, so they
+	// can be read-only once populated. InstallOrFail and InstallTemp make
+	// them writable again at cleanup, use RemoveAll for other installs.
+	ModeExact
+)
+
+func (p ModePolicy) String() string {
+	switch p {
+	case ModeMinimum:
+		return "minimum"
+	case ModeMinimumChmod:
+		return "minimum-chmod"
+	case ModeExact:
+		return "exact"
+	}
+	return fmt.Sprintf("ModePolicy(%d)", int(p))
+}
+
+// WithModePolicy sets the permissions of installed files and directories. The
+// default is ModeMinimum.
+func WithModePolicy(policy ModePolicy) Option {
+	return func(o *options) { o.modes = policy }
+}
+
+// WithSourceModTimes sets the mod times of installed files and directories to
+// those in the skeleton. Paths without a mod time in the skeleton, like those
+// of a Tree, keep their install time.
+func WithSourceModTimes() Option {
+	return func(o *options) {
+		o.modTimes = func(info fs.FileInfo) time.Time { return info.ModTime() }
+	}
+}
+
+// WithFixedModTime sets the mod times of installed files and directories to
+// the time, for deterministic trees.
+func WithFixedModTime(t time.Time) Option {
+	return func(o *options) {
+		o.modTimes = func(fs.FileInfo) time.Time { return t }
+	}
+}
+
+// applyMetadata sets the modes and mod times of the installed paths, deepest
+// first, and records the resulting metadata in the report. Symlinks and the
+// install dir itself are left as they are, as are paths kept by ConflictSkip.
+func (o options) applyMetadata(dir string, report *InstallReport) error {
+	for i := len(report.Entries) - 1; i >= 0; i-- {
+		entry := &report.Entries[i]
+		installPath := filepath.Join(dir, filepath.FromSlash(entry.Path))
+
+		apply := entry.Path != "." && entry.Action != ActionSkipped && !isSymlink(entry.Type)
+		if apply && o.modes != ModeMinimum {
+			perm := entry.source.Mode().Perm()
+			if o.modes == ModeMinimumChmod {
+				perm |= minimumPerm(entry.Type)
+			}
+			if err := os.Chmod(installPath, perm); err != nil {
+				return SkeletonInstallError{Path: entry.Source, Err: fmt.Errorf("chmod: %w", err)}
+			}
+		}
+		if apply && o.modTimes != nil {
+			if mtime := o.modTimes(entry.source); !mtime.IsZero() {
+				if err := os.Chtimes(installPath, mtime, mtime); err != nil {
+					return SkeletonInstallError{Path: entry.Source, Err: fmt.Errorf("chtimes: %w", err)}
+				}
+			}
+		}
+
+		info, err := os.Lstat(installPath)
+		if err != nil {
+			return SkeletonInstallError{Path: entry.Source, Err: fmt.Errorf("stat installed: %w", err)}
+		}
+		entry.Perm = info.Mode().Perm()
+		entry.ModTime = info.ModTime()
+	}
+	return nil
+}
+
+func minimumPerm(typ fs.FileMode) fs.FileMode {
+	if typ.IsDir() {
+		return MinimumDirPerm
+	}
+	return MinimumFilePerm
+}
+
+// Manifest lists the installed paths with their resulting metadata, one per
+// line, like:
+//
+//	drwxr-x--- 2024-01-02T03:04:05Z created   etc
+//	-r--r----- 2024-01-02T03:04:05Z created   etc/app.conf
+//	Lrwxrwxrwx 2024-01-02T03:04:05Z unchanged current
+func (r *InstallReport) Manifest() string {
+	var b strings.Builder
+	for _, e := range r.Entries {
+		fmt.Fprintf(&b, "%v %s %-11s %s\n", e.Type|e.Perm, e.ModTime.UTC().Format(time.RFC3339), e.Action, e.Path)
+	}
+	return b.String()
+}
+
+// RemoveAll removes the dir and the tree installed in it, like os.RemoveAll,
+// including any read-only directories left by ModeExact.
+func RemoveAll(dir string) error {
+	filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err == nil && d.IsDir() {
+			os.Chmod(p, 0700)
+		}
+		return nil
+	})
+	return os.RemoveAll(dir)
+}
+
+// restoreWritable registers a cleanup giving the owner back full access to
+// the installed directories the report left without it, so that the test's
+// directories can be removed.
+func restoreWritable(cleanuper testthings.Cleanuper, dir string, report *InstallReport) {
+	var restore []InstallEntry
+	for _, e := range report.Entries {
+		if e.Type.IsDir() && e.Path != "." && e.Action != ActionSkipped && e.Perm&0700 != 0700 {
+			restore = append(restore, e)
+		}
+	}
+	if len(restore) == 0 {
+		return
+	}
+
+	cleanuper.Cleanup(func() {
+		// parents first, so their children can be reached.
+		for _, e := range restore {
+			os.Chmod(filepath.Join(dir, filepath.FromSlash(e.Path)), e.Perm|0700)
+		}
+	})
+}
@@ -0,0 +1,112 @@
+package skeletonfs_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jahkeup/testthings/skeletonfs"
+)
+
+func TestSkeletonFS_exactModes(t *testing.T) {
+	fixed := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	skel := skeletonfs.SkeletonFS(skeletonfs.Tree{
+		"ro":          skeletonfs.Dir{Mode: 0555},
+		"ro/secret":   skeletonfs.File{Contents: "s", Mode: 0400},
+		"ro/nested":   skeletonfs.Dir{Mode: 0500},
+		"ro/nested/x": skeletonfs.File{Contents: "x", Mode: 0444},
+		"current":     skeletonfs.Symlink("ro"),
+	}.FS(),
+		skeletonfs.WithModePolicy(skeletonfs.ModeExact),
+		skeletonfs.WithFixedModTime(fixed),
+	)
+
+	t.Run("install", func(t *testing.T) {
+		// the read-only dirs mustn't keep the TempDir from being removed.
+		installDir := t.TempDir()
+		skel.InstallOrFail(t, installDir)
+
+		for path, perm := range map[string]fs.FileMode{
+			"ro":          0555,
+			"ro/secret":   0400,
+			"ro/nested":   0500,
+			"ro/nested/x": 0444,
+		} {
+			info, err := os.Lstat(filepath.Join(installDir, path))
+			if assert.NoError(t, err) {
+				assert.Equalf(t, perm, info.Mode().Perm(), "for %q", path)
+				assert.Truef(t, fixed.Equal(info.ModTime()), "mod time for %q: %v", path, info.ModTime())
+			}
+		}
+	})
+
+	t.Run("manifest", func(t *testing.T) {
+		installDir := filepath.Join(t.TempDir(), "install")
+		t.Cleanup(func() { skeletonfs.RemoveAll(installDir) })
+		report, err := skel.InstallWithReport(installDir)
+		require.NoError(t, err)
+
+		manifest := report.Manifest()
+		t.Log("\n" + manifest)
+		assert.Contains(t, manifest, "dr-x------ 2024-01-02T03:04:05Z created     ro/nested\n")
+		assert.Contains(t, manifest, "-r-------- 2024-01-02T03:04:05Z created     ro/secret\n")
+		assert.Contains(t, manifest, "Lrwxrwxrwx")
+
+		require.NoError(t, skeletonfs.RemoveAll(installDir))
+		assert.NoDirExists(t, installDir)
+	})
+}
+
+func TestSkeletonFS_minimumChmod(t *testing.T) {
+	skel := fstest.MapFS{
+		"shared":   &fstest.MapFile{Data: []byte("rw"), Mode: 0666},
+		"no-perms": &fstest.MapFile{Data: []byte("none")},
+		"dir/file": &fstest.MapFile{Data: []byte("f"), Mode: 0644},
+	}
+
+	installDir := skeletonfs.SkeletonFS(skel, skeletonfs.WithModePolicy(skeletonfs.ModeMinimumChmod)).InstallTemp(t)
+	for path, perm := range map[string]fs.FileMode{
+		"shared":   0666,
+		"no-perms": skeletonfs.MinimumFilePerm,
+		"dir/file": 0644,
+	} {
+		info, err := os.Stat(filepath.Join(installDir, path))
+		if assert.NoError(t, err) {
+			assert.Equalf(t, perm, info.Mode().Perm(), "should ignore the umask for %q", path)
+		}
+	}
+}
+
+func TestSkeletonFS_sourceModTimes(t *testing.T) {
+	past := time.Date(2020, 6, 1, 12, 0, 0, 0, time.UTC)
+	srcDir := skeletonfs.SkeletonFS(skeletonfs.Tree{"etc/app.conf": "conf"}.FS()).InstallTemp(t)
+	require.NoError(t, os.Chtimes(filepath.Join(srcDir, "etc", "app.conf"), past, past))
+	require.NoError(t, os.Chtimes(filepath.Join(srcDir, "etc"), past.Add(time.Hour), past.Add(time.Hour)))
+
+	captured, err := skeletonfs.Capture(srcDir)
+	require.NoError(t, err)
+	installDir := t.TempDir()
+	report, err := skeletonfs.SkeletonFS(captured, skeletonfs.WithSourceModTimes()).InstallWithReport(installDir)
+	require.NoError(t, err)
+
+	for path, expected := range map[string]time.Time{
+		"etc/app.conf": past,
+		"etc":          past.Add(time.Hour),
+	} {
+		info, err := os.Stat(filepath.Join(installDir, path))
+		if assert.NoError(t, err) {
+			assert.Truef(t, expected.Equal(info.ModTime()), "mod time for %q: %v", path, info.ModTime())
+		}
+		for _, e := range report.Entries {
+			if e.Path == path {
+				assert.Truef(t, expected.Equal(e.ModTime), "should report the mod time for %q", path)
+			}
+		}
+	}
+}
@@ -0,0 +1,52 @@
+package skeletonfs
+
+import (
+	"io/fs"
+	"time"
+)
+
+// Option configures how a skeleton is installed.
+type Option func(*options)
+
+type options struct {
+	symlinks  SymlinkPolicy
+	conflicts ConflictPolicy
+	merge     MergeFunc
+	templates *templateOptions
+	filters   filterOptions
+	modes     ModePolicy
+	modTimes  func(fs.FileInfo) time.Time
+
+	// selected are the paths within the subtree to install, when filtering
+	// includes only some.
+	selected map[string]bool
+}
+
+func newOptions(opts []Option) options {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// WithSymlinkPolicy sets how symlinks with absolute targets, or targets that
+// escape the skeleton, are installed. The default is SymlinkSkip.
+func WithSymlinkPolicy(policy SymlinkPolicy) Option {
+	return func(o *options) { o.symlinks = policy }
+}
+
+// WithConflictPolicy sets how the skeleton's files are installed over
+// existing ones. The default is ConflictFail.
+func WithConflictPolicy(policy ConflictPolicy) Option {
+	return func(o *options) { o.conflicts = policy }
+}
+
+// WithMerge merges the skeleton's files into existing ones with the function,
+// setting the ConflictMerge policy.
+func WithMerge(merge MergeFunc) Option {
+	return func(o *options) {
+		o.conflicts = ConflictMerge
+		o.merge = merge
+	}
+}
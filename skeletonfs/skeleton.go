@@ -3,7 +3,6 @@ package skeletonfs
 import (
 	"errors"
 	"fmt"
-	"io"
 	"io/fs"
 	"os"
 	"path/filepath"
@@ -13,10 +12,10 @@ import (
 
 const (
 	// MinimumFilePerm is the minimum allowed permission bits that skeleton will
-	// create during install.
+	// create during install, unless ModeExact is used.
 	MinimumFilePerm os.FileMode = 0440
 	// MinimumDirPerm is the minimum allowed permission bits that skeleton will
-	// create during install.
+	// create during install, unless ModeExact is used.
 	MinimumDirPerm os.FileMode = 0700
 )
 
@@ -43,31 +42,75 @@ var _ error = (*SkeletonInstallError)(nil)
 
 // SkeletonFS is used to create new copies of the fsys in test case directories.
 //
-// Skeleton installation has no support for nodes other than directories and
-// regular files.
-func SkeletonFS(fsys fs.FS) skeletonFS {
-	return skeletonFS{fsys}
+// Skeleton installation supports directories, regular files, and symlinks
+// when the fsys is a ReadLinkFS (see DirFS). Other nodes are skipped, as are
+// symlinks with absolute or escaping targets unless WithSymlinkPolicy says
+// otherwise.
+func SkeletonFS(fsys fs.FS, opts ...Option) skeletonFS {
+	return skeletonFS{skeleton: fsys, options: newOptions(opts)}
 }
 
 type skeletonFS struct {
 	skeleton fs.FS
+	options  options
 }
 
 // Install the skeleton into the provided directory.
 func (skel skeletonFS) Install(dir string) error {
+	_, err := skel.InstallWithReport(dir)
+	return err
+}
+
+// InstallWithReport installs the skeleton into the provided directory,
+// reporting the action taken for each path. The report covers the paths
+// installed before any error.
+func (skel skeletonFS) InstallWithReport(dir string) (*InstallReport, error) {
+	report := &InstallReport{}
 	if err := os.MkdirAll(dir, 0750); err != nil {
-		return fmt.Errorf("dir: %w", err)
+		return report, fmt.Errorf("dir: %w", err)
+	}
+
+	opts := skel.options
+	if root := opts.filters.walkRoot(); root != "." {
+		info, err := fs.Stat(skel.skeleton, root)
+		if err != nil {
+			return report, SkeletonInstallError{Path: root, Err: fmt.Errorf("subtree: %w", err)}
+		}
+		if !info.IsDir() {
+			return report, SkeletonInstallError{Path: root, Err: fmt.Errorf("subtree: not a directory")}
+		}
+	}
+	selected, err := opts.filters.selectPaths(skel.skeleton)
+	if err != nil {
+		return report, SkeletonInstallError{Path: opts.filters.walkRoot(), Err: fmt.Errorf("walk: %w", err)}
 	}
+	opts.selected = selected
 
-	return fs.WalkDir(skel.skeleton, ".",
+	err = fs.WalkDir(skel.skeleton, opts.filters.walkRoot(),
 		ignoreNodeTypeErrors(
-			skeletonInstaller(skel.skeleton, dir)))
+			skeletonInstaller(skel.skeleton, dir, opts, report)))
+	if err != nil {
+		return report, err
+	}
+	return report, opts.applyMetadata(dir, report)
 }
 
 // InstallOrFail will install the skeleton into the provided directory. Or.. it
 // fails the test run.
+//
+// When testingT is a Cleanuper, directories left read-only by ModeExact are
+// made writable again at cleanup, so that a dir from t.TempDir can be removed.
 func (skel skeletonFS) InstallOrFail(testingT testthings.Terminator, dir string) {
-	err := skel.Install(dir)
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	report, err := skel.InstallWithReport(dir)
+	if cleanuper, ok := testingT.(testthings.Cleanuper); ok {
+		restoreWritable(cleanuper, dir, report)
+	}
 	if err != nil {
 		testingT.Fatal(fmt.Sprintf("skeleton install: %v", err))
 	}
@@ -91,7 +134,7 @@ func ignoreNodeTypeErrors(fn fs.WalkDirFunc) fs.WalkDirFunc {
 	}
 }
 
-func skeletonInstaller(skelFS fs.FS, dir string) fs.WalkDirFunc {
+func skeletonInstaller(skelFS fs.FS, dir string, opts options, report *InstallReport) fs.WalkDirFunc {
 	installPath := func(p ...string) string {
 		elms := append([]string{dir}, p...)
 		return filepath.Join(elms...)
@@ -106,22 +149,43 @@ func skeletonInstaller(skelFS fs.FS, dir string) fs.WalkDirFunc {
 			return SkeletonInstallError{Path: path, Err: fmt.Errorf("info: %w", err)}
 		}
 
+		name, ok, err := opts.installName(path, info.Mode())
+		if err != nil {
+			return SkeletonInstallError{Path: path, Err: err}
+		}
+		if !ok {
+			if info.IsDir() && opts.skipsDir(path) {
+				return fs.SkipDir
+			}
+			return nil
+		}
+		if name != "." {
+			// renamed paths may not have their parents installed
+			if err := os.MkdirAll(filepath.Dir(installPath(name)), 0750); err != nil {
+				return SkeletonInstallError{Path: path, Err: err}
+			}
+		}
+
 		if info.IsDir() {
-			err := os.MkdirAll(installPath(path), info.Mode()|MinimumDirPerm)
+			action := ActionCreated
+			if _, err := os.Lstat(installPath(name)); err == nil {
+				action = ActionUnchanged
+			}
+			err := os.MkdirAll(installPath(name), info.Mode()|MinimumDirPerm)
 			if err != nil {
 				return SkeletonInstallError{Path: path, Err: err, fileMode: info.Mode()}
 			}
+			report.record(name, path, info, action)
 			return nil
 		}
 
-		// TODO: maybe handle symlinks?
-		//
-		// seems like it could be useful, but might breed complex test
-		// filesystem setup (which isn't something I want this library to
-		// encourage). That can be dealt with by callers who can prepare their
-		// directories however they want (merging or modifying the target tree).
 		if isSymlink(info.Mode()) {
-			return SkeletonInstallError{Path: path, Err: errors.New("unsupported file type: symlink"), fileMode: info.Mode()}
+			action, err := installSymlink(skelFS, dir, path, name, info, opts)
+			if err != nil {
+				return err
+			}
+			report.record(name, path, info, action)
+			return nil
 		}
 
 		// Catch the rest - deal only with regular files.
@@ -129,24 +193,23 @@ func skeletonInstaller(skelFS fs.FS, dir string) fs.WalkDirFunc {
 			return SkeletonInstallError{Path: path, Err: fmt.Errorf("unsupported file type: %v", info.Mode()), fileMode: info.Mode()}
 		}
 
-		skelF, err := skelFS.Open(path)
+		data, err := fs.ReadFile(skelFS, path)
 		if err != nil {
-			return SkeletonInstallError{Path: path, Err: fmt.Errorf("open skel file: %w", err), fileMode: info.Mode()}
+			return SkeletonInstallError{Path: path, Err: fmt.Errorf("read skel file: %w", err), fileMode: info.Mode()}
 		}
-		defer skelF.Close()
-
-		// NOTE: will fail on conflicts
-		installF, err := os.OpenFile(installPath(path), os.O_WRONLY|os.O_EXCL|os.O_CREATE, info.Mode()|MinimumFilePerm)
-		if err != nil {
-			return SkeletonInstallError{Path: path, Err: fmt.Errorf("install file: %w", err)}
+		if opts.templates.rendered(path) {
+			data, err = opts.templates.render(path, data)
+			if err != nil {
+				return SkeletonInstallError{Path: path, Err: fmt.Errorf("template: %w", err)}
+			}
 		}
-		defer installF.Close()
 
-		_, err = io.Copy(installF, skelF)
+		action, err := writeFile(name, installPath(name), data, info.Mode()|MinimumFilePerm, opts)
 		if err != nil {
 			return SkeletonInstallError{Path: path, Err: err}
 		}
-		return installF.Close()
+		report.record(name, path, info, action)
+		return nil
 	}
 }
 
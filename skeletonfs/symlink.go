@@ -0,0 +1,153 @@
+package skeletonfs
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+)
+
+// ReadLinkFS is a fs.FS that exposes its symlinks, so that they can be
+// installed. Skeletons from file systems without ReadLink skip symlinks.
+type ReadLinkFS interface {
+	fs.FS
+
+	// ReadLink returns the target of the symlink.
+	ReadLink(name string) (string, error)
+	// Lstat returns the info of the file without following symlinks.
+	Lstat(name string) (fs.FileInfo, error)
+}
+
+// SymlinkPolicy decides how symlinks with absolute targets, or with relative
+// targets escaping the skeleton, are installed. Symlinks to targets within the
+// skeleton are always installed as-is.
+type SymlinkPolicy int
+
+const (
+	// SymlinkSkip leaves the symlink out, as skeletons without symlink
+	// support always did.
+	SymlinkSkip SymlinkPolicy = iota
+	// SymlinkReject fails the install.
+	SymlinkReject
+	// SymlinkRewrite rewrites the target relative to the install dir: absolute
+	// targets are rooted in the install dir and escaping targets are clamped
+	// to it.
+	SymlinkRewrite
+	// SymlinkAllow installs the target as-is.
+	SymlinkAllow
+)
+
+func (p SymlinkPolicy) String() string {
+	switch p {
+	case SymlinkSkip:
+		return "skip"
+	case SymlinkReject:
+		return "reject"
+	case SymlinkRewrite:
+		return "rewrite"
+	case SymlinkAllow:
+		return "allow"
+	}
+	return fmt.Sprintf("SymlinkPolicy(%d)", int(p))
+}
+
+// DirFS is os.DirFS with ReadLink and Lstat, exposing the symlinks in the
+// directory for installs.
+func DirFS(dir string) ReadLinkFS {
+	return dirFS{FS: os.DirFS(dir), dir: dir}
+}
+
+type dirFS struct {
+	fs.FS
+	dir string
+}
+
+func (fsys dirFS) ReadLink(name string) (string, error) {
+	if !fs.ValidPath(name) {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	target, err := os.Readlink(filepath.Join(fsys.dir, filepath.FromSlash(name)))
+	if err != nil {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: unwrapPathError(err)}
+	}
+	return filepath.ToSlash(target), nil
+}
+
+func (fsys dirFS) Lstat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrInvalid}
+	}
+	info, err := os.Lstat(filepath.Join(fsys.dir, filepath.FromSlash(name)))
+	if err != nil {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: unwrapPathError(err)}
+	}
+	return info, nil
+}
+
+// unwrapPathError drops the os level path, which would otherwise leak the
+// directory into the fs.FS's errors.
+func unwrapPathError(err error) error {
+	if perr, ok := err.(*fs.PathError); ok {
+		return perr.Err
+	}
+	return err
+}
+
+// symlinkTarget resolves the target of the symlink at name to install,
+// applying the policy to absolute and escaping targets.
+func symlinkTarget(name, target string, policy SymlinkPolicy) (string, error) {
+	escapes := path.IsAbs(target) ||
+		!fs.ValidPath(path.Join(path.Dir(name), target))
+	if !escapes || policy == SymlinkAllow {
+		return filepath.FromSlash(target), nil
+	}
+
+	switch policy {
+	case SymlinkSkip, SymlinkReject:
+		return "", fmt.Errorf("symlink target %q escapes the skeleton", target)
+	case SymlinkRewrite:
+		// resolve from the root to clamp the target within the skeleton.
+		resolved := path.Clean(target)
+		if !path.IsAbs(target) {
+			resolved = path.Join("/", path.Dir(name), target)
+		}
+		rel, err := filepath.Rel(
+			filepath.FromSlash(path.Join("/", path.Dir(name))),
+			filepath.FromSlash(resolved))
+		if err != nil {
+			return "", fmt.Errorf("rewrite symlink target %q: %w", target, err)
+		}
+		return rel, nil
+	}
+	return "", fmt.Errorf("unknown symlink policy: %v", policy)
+}
+
+// installSymlink creates the skeleton's symlink at source as name within dir,
+// if the skeleton exposes its target.
+func installSymlink(skelFS fs.FS, dir, source, name string, info fs.FileInfo, opts options) (Action, error) {
+	linkFS, ok := skelFS.(ReadLinkFS)
+	if !ok {
+		// no way to tell where it goes, so leave it out.
+		return 0, SkeletonInstallError{Path: source, Err: fmt.Errorf("unsupported file type: symlink"), fileMode: info.Mode()}
+	}
+
+	target, err := linkFS.ReadLink(source)
+	if err != nil {
+		return 0, SkeletonInstallError{Path: source, Err: fmt.Errorf("read link: %w", err)}
+	}
+	target, err = symlinkTarget(name, target, opts.symlinks)
+	if err != nil {
+		if opts.symlinks == SymlinkSkip {
+			// leave it out, like the symlinks of skeletons without ReadLink.
+			return 0, SkeletonInstallError{Path: source, Err: err, fileMode: info.Mode()}
+		}
+		return 0, SkeletonInstallError{Path: source, Err: err}
+	}
+
+	action, err := writeSymlink(filepath.Join(dir, filepath.FromSlash(name)), target, opts)
+	if err != nil {
+		return 0, SkeletonInstallError{Path: source, Err: err}
+	}
+	return action, nil
+}
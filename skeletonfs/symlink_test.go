@@ -0,0 +1,118 @@
+package skeletonfs_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jahkeup/testthings/skeletonfs"
+)
+
+// symlinkedSource creates a release layout with a relative symlink, plus any
+// extra links given.
+func symlinkedSource(t *testing.T, links map[string]string) string {
+	src := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(src, "releases", "v2"), 0750))
+	require.NoError(t, os.WriteFile(filepath.Join(src, "releases", "v2", "app.conf"), []byte("v2"), 0640))
+	require.NoError(t, os.Symlink(filepath.Join("releases", "v2"), filepath.Join(src, "current")))
+	for name, target := range links {
+		require.NoError(t, os.Symlink(target, filepath.Join(src, name)))
+	}
+	return src
+}
+
+func TestSkeletonFS_symlinks(t *testing.T) {
+	t.Run("relative", func(t *testing.T) {
+		src := symlinkedSource(t, nil)
+
+		installDir := t.TempDir()
+		require.NoError(t, skeletonfs.SkeletonFS(skeletonfs.DirFS(src)).Install(installDir))
+
+		target, err := os.Readlink(filepath.Join(installDir, "current"))
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join("releases", "v2"), target)
+		data, err := os.ReadFile(filepath.Join(installDir, "current", "app.conf"))
+		assert.NoError(t, err, "should resolve within the install dir")
+		assert.Equal(t, "v2", string(data))
+	})
+
+	t.Run("skip", func(t *testing.T) {
+		src := symlinkedSource(t, map[string]string{"escape": "../outside", "abs": "/etc/hostname"})
+
+		installDir := t.TempDir()
+		require.NoError(t, skeletonfs.SkeletonFS(skeletonfs.DirFS(src)).Install(installDir))
+
+		for _, name := range []string{"escape", "abs"} {
+			_, err := os.Lstat(filepath.Join(installDir, name))
+			assert.ErrorIsf(t, err, fs.ErrNotExist, "should skip %q by default", name)
+		}
+		_, err := os.Readlink(filepath.Join(installDir, "current"))
+		assert.NoError(t, err, "should install links within the skeleton")
+	})
+
+	t.Run("reject", func(t *testing.T) {
+		src := symlinkedSource(t, map[string]string{"escape": "../outside"})
+
+		err := skeletonfs.SkeletonFS(skeletonfs.DirFS(src),
+			skeletonfs.WithSymlinkPolicy(skeletonfs.SymlinkReject)).Install(t.TempDir())
+		var installErr skeletonfs.SkeletonInstallError
+		require.ErrorAs(t, err, &installErr)
+		assert.Equal(t, "escape", installErr.Path)
+		assert.Contains(t, err.Error(), "escapes the skeleton")
+	})
+
+	t.Run("rewrite", func(t *testing.T) {
+		src := symlinkedSource(t, map[string]string{
+			"escape":                 "../../outside",
+			"abs":                    "/releases/v2/app.conf",
+			"releases/v2/previous":   "../../../v1",
+			"releases/v2/also-inner": "../v2/app.conf",
+		})
+
+		installDir := t.TempDir()
+		require.NoError(t, skeletonfs.SkeletonFS(skeletonfs.DirFS(src),
+			skeletonfs.WithSymlinkPolicy(skeletonfs.SymlinkRewrite)).Install(installDir))
+
+		for name, expected := range map[string]string{
+			"escape":                 "outside",
+			"abs":                    "releases/v2/app.conf",
+			"releases/v2/previous":   "../../v1",
+			"releases/v2/also-inner": "../v2/app.conf",
+		} {
+			target, err := os.Readlink(filepath.Join(installDir, name))
+			if assert.NoError(t, err) {
+				assert.Equalf(t, filepath.FromSlash(expected), target, "for %q", name)
+			}
+		}
+	})
+
+	t.Run("allow", func(t *testing.T) {
+		src := symlinkedSource(t, map[string]string{"abs": "/etc/hostname"})
+
+		installDir := t.TempDir()
+		require.NoError(t, skeletonfs.SkeletonFS(skeletonfs.DirFS(src),
+			skeletonfs.WithSymlinkPolicy(skeletonfs.SymlinkAllow)).Install(installDir))
+
+		target, err := os.Readlink(filepath.Join(installDir, "abs"))
+		require.NoError(t, err)
+		assert.Equal(t, "/etc/hostname", target)
+	})
+
+	t.Run("source without links", func(t *testing.T) {
+		src := symlinkedSource(t, map[string]string{"escape": "../outside"})
+
+		installDir := t.TempDir()
+		// hide any ReadLink the os.DirFS may have
+		fsys := struct{ fs.FS }{os.DirFS(src)}
+		require.NoError(t, skeletonfs.SkeletonFS(fsys).Install(installDir))
+
+		_, err := os.Lstat(filepath.Join(installDir, "current"))
+		assert.ErrorIs(t, err, fs.ErrNotExist, "should skip symlinks")
+		_, err = os.Stat(filepath.Join(installDir, "releases", "v2", "app.conf"))
+		assert.NoError(t, err)
+	})
+}
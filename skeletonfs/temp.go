@@ -0,0 +1,97 @@
+package skeletonfs
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	"github.com/jahkeup/testthings"
+)
+
+// InstallTemp installs the skeleton into a new temporary directory, returning
+// its path, or fails the test.
+//
+// When testingT can report whether the test Failed and supports Cleanup (like
+// testing.TB), the directory is removed at cleanup unless the test failed, in
+// which case it's kept and its path logged for inspection. Otherwise, the
+// directory comes from testingT's TempDir method if it has one, or is removed
+// at cleanup when testingT is a Cleanuper. Directories left read-only by
+// ModeExact don't keep either from being removed.
+func (skel skeletonFS) InstallTemp(testingT testthings.Terminator) string {
+	if th, ok := testingT.(interface {
+		Helper()
+	}); ok {
+		th.Helper()
+	}
+
+	dir, fromTempDir, err := tempDir(testingT)
+	if err != nil {
+		testingT.Fatal(fmt.Sprintf("skeleton install: temp dir: %v", err))
+		return ""
+	}
+
+	report, err := skel.InstallWithReport(dir)
+	if cleanuper, ok := testingT.(testthings.Cleanuper); ok && fromTempDir {
+		// the TempDir is removed by testingT, not RemoveAll.
+		restoreWritable(cleanuper, dir, report)
+	}
+	if err != nil {
+		testingT.Fatal(fmt.Sprintf("skeleton install: %v", err))
+		return dir
+	}
+	return dir
+}
+
+// tempDir creates the temporary directory, reporting whether it came from
+// testingT's TempDir method.
+func tempDir(testingT testthings.Terminator) (string, bool, error) {
+	failer, canFail := testingT.(interface{ Failed() bool })
+	cleanuper, canCleanup := testingT.(testthings.Cleanuper)
+	if tempDirer, ok := testingT.(interface{ TempDir() string }); ok && !(canFail && canCleanup) {
+		return tempDirer.TempDir(), true, nil
+	}
+
+	dir, err := os.MkdirTemp("", tempPattern(testingT))
+	if err != nil {
+		return "", false, err
+	}
+
+	logger, canLog := testingT.(testthings.Logger)
+	switch {
+	case canFail && canCleanup:
+		cleanuper.Cleanup(func() {
+			if failer.Failed() {
+				if canLog {
+					logger.Log(fmt.Sprintf("skeleton install: kept %s for the failed test", dir))
+				}
+				return
+			}
+			RemoveAll(dir)
+		})
+	case canCleanup:
+		cleanuper.Cleanup(func() { RemoveAll(dir) })
+	case canLog:
+		logger.Log(fmt.Sprintf("skeleton install: created %s, it won't be removed", dir))
+	}
+	return dir, false, nil
+}
+
+// tempPattern names the temporary directory after the test, if it's named.
+func tempPattern(testingT testthings.Terminator) string {
+	namer, ok := testingT.(interface{ Name() string })
+	if !ok {
+		return "skeleton-*"
+	}
+
+	name := strings.Map(func(r rune) rune {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' || r == '_' {
+			return r
+		}
+		return '_'
+	}, namer.Name())
+	if len(name) > 64 {
+		name = name[:64]
+	}
+	return name + "-skeleton-*"
+}
@@ -0,0 +1,113 @@
+package skeletonfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jahkeup/testthings/skeletonfs"
+)
+
+type tempTB struct {
+	fatals
+	failed   bool
+	logs     []string
+	cleanups []func()
+}
+
+func (tb *tempTB) Failed() bool      { return tb.failed || len(tb.msgs) > 0 }
+func (tb *tempTB) Cleanup(fn func()) { tb.cleanups = append(tb.cleanups, fn) }
+func (tb *tempTB) Log(args ...any)   { tb.logs = append(tb.logs, args[0].(string)) }
+func (tb *tempTB) Name() string      { return "TestInstallTemp/sub test" }
+
+func (tb *tempTB) cleanup() {
+	for i := len(tb.cleanups) - 1; i >= 0; i-- {
+		tb.cleanups[i]()
+	}
+}
+
+var tempSkel = fstest.MapFS{
+	"etc/app.conf": &fstest.MapFile{Data: []byte("conf"), Mode: 0444},
+}
+
+func TestInstallTemp(t *testing.T) {
+	dir := skeletonfs.SkeletonFS(tempSkel).InstallTemp(t)
+	data, err := os.ReadFile(filepath.Join(dir, "etc", "app.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, "conf", string(data))
+}
+
+func TestInstallTemp_cleanup(t *testing.T) {
+	t.Run("passed", func(t *testing.T) {
+		tb := &tempTB{}
+		dir := skeletonfs.SkeletonFS(tempSkel).InstallTemp(tb)
+		assert.True(t, strings.HasPrefix(filepath.Base(dir), "TestInstallTemp_sub_test-skeleton-"), dir)
+		assert.FileExists(t, filepath.Join(dir, "etc", "app.conf"))
+
+		tb.cleanup()
+		assert.NoDirExists(t, dir, "should remove the dir")
+		assert.Empty(t, tb.logs)
+	})
+
+	t.Run("failed", func(t *testing.T) {
+		tb := &tempTB{}
+		dir := skeletonfs.SkeletonFS(tempSkel).InstallTemp(tb)
+		t.Cleanup(func() { os.RemoveAll(dir) })
+
+		tb.failed = true
+		tb.cleanup()
+		assert.DirExists(t, dir, "should keep the dir for the failed test")
+		require.Len(t, tb.logs, 1)
+		assert.Contains(t, tb.logs[0], dir)
+	})
+
+	t.Run("install error", func(t *testing.T) {
+		tb := &tempTB{}
+		dir := skeletonfs.SkeletonFS(fstest.MapFS{
+			"bad.tmpl": &fstest.MapFile{Data: []byte("{{")},
+		}, skeletonfs.WithTemplateData(nil)).InstallTemp(tb)
+		t.Cleanup(func() { os.RemoveAll(dir) })
+		require.Len(t, tb.msgs, 1)
+		assert.Contains(t, tb.msgs[0], "bad.tmpl")
+
+		tb.cleanup()
+		assert.DirExists(t, dir, "should keep the dir for the failed install")
+	})
+
+	t.Run("temp dir", func(t *testing.T) {
+		// without Failed, the TempDir is used and removed by testingT.
+		tb := &tempDirTB{dir: t.TempDir()}
+		dir := skeletonfs.SkeletonFS(skeletonfs.Tree{
+			"ro":      skeletonfs.Dir{Mode: 0500},
+			"ro/file": skeletonfs.File{Contents: "f", Mode: 0400},
+		}.FS(), skeletonfs.WithModePolicy(skeletonfs.ModeExact)).InstallTemp(tb)
+		assert.Equal(t, tb.dir, dir)
+		require.Empty(t, tb.msgs)
+
+		tb.cleanup()
+		info, err := os.Stat(filepath.Join(dir, "ro"))
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0700), info.Mode().Perm(), "should make the dir removable")
+	})
+}
+
+// tempDirTB has a TempDir, but can't tell whether the test failed.
+type tempDirTB struct {
+	fatals
+	dir      string
+	cleanups []func()
+}
+
+func (tb *tempDirTB) TempDir() string   { return tb.dir }
+func (tb *tempDirTB) Cleanup(fn func()) { tb.cleanups = append(tb.cleanups, fn) }
+
+func (tb *tempDirTB) cleanup() {
+	for i := len(tb.cleanups) - 1; i >= 0; i-- {
+		tb.cleanups[i]()
+	}
+}
@@ -0,0 +1,111 @@
+package skeletonfs
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+)
+
+// TemplateSuffix is stripped from the names of rendered files.
+const TemplateSuffix = ".tmpl"
+
+type templateOptions struct {
+	data    any
+	pattern string
+	funcs   template.FuncMap
+}
+
+// WithTemplateData renders the skeleton's files named with the TemplateSuffix
+// (or matching WithTemplatePattern) through text/template with the data,
+// installing them without the suffix. Path names containing actions, like
+// "{{.Name}}/app.conf", are rendered too.
+func WithTemplateData(data any) Option {
+	return func(o *options) {
+		o.template().data = data
+	}
+}
+
+// WithTemplatePattern renders the files whose base name matches the
+// path.Match pattern, instead of only those with the TemplateSuffix.
+func WithTemplatePattern(pattern string) Option {
+	return func(o *options) {
+		o.template().pattern = pattern
+	}
+}
+
+// WithTemplateFuncs adds the functions to the templates.
+func WithTemplateFuncs(funcs template.FuncMap) Option {
+	return func(o *options) {
+		t := o.template()
+		if t.funcs == nil {
+			t.funcs = template.FuncMap{}
+		}
+		for name, fn := range funcs {
+			t.funcs[name] = fn
+		}
+	}
+}
+
+func (o *options) template() *templateOptions {
+	if o.templates == nil {
+		o.templates = &templateOptions{pattern: "*" + TemplateSuffix}
+	}
+	return o.templates
+}
+
+// rendered reports whether the skeleton's file is rendered as a template.
+func (t *templateOptions) rendered(name string) bool {
+	if t == nil {
+		return false
+	}
+	matched, _ := path.Match(t.pattern, path.Base(name))
+	return matched
+}
+
+// installName renders the skeleton's path name, stripping the TemplateSuffix
+// from rendered files.
+func (t *templateOptions) installName(name string, mode fs.FileMode) (string, error) {
+	if t == nil {
+		return name, nil
+	}
+
+	segments := strings.Split(name, "/")
+	for i, segment := range segments {
+		if !strings.Contains(segment, "{{") {
+			continue
+		}
+		rendered, err := t.render(segment, []byte(segment))
+		if err != nil {
+			return "", fmt.Errorf("template path: %w", err)
+		}
+		segments[i] = string(rendered)
+	}
+
+	installName := strings.Join(segments, "/")
+	if mode.IsRegular() && t.rendered(name) {
+		installName = strings.TrimSuffix(installName, TemplateSuffix)
+	}
+	if !fs.ValidPath(installName) || (installName == "." && name != ".") {
+		return "", fmt.Errorf("template path: rendered invalid path %q", installName)
+	}
+	return installName, nil
+}
+
+func (t *templateOptions) render(name string, text []byte) ([]byte, error) {
+	tmpl, err := template.New(name).
+		Option("missingkey=error").
+		Funcs(t.funcs).
+		Parse(string(text))
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, t.data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
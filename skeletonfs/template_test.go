@@ -0,0 +1,93 @@
+package skeletonfs_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jahkeup/testthings/skeletonfs"
+)
+
+type templateData struct {
+	Dir  string
+	Port int
+	Name string
+}
+
+func TestSkeletonFS_templates(t *testing.T) {
+	skel := fstest.MapFS{
+		"etc/app.conf.tmpl":      &fstest.MapFile{Data: []byte("dir={{.Dir}} port={{.Port}} name={{upper .Name}}")},
+		"{{.Name}}/data.txt":     &fstest.MapFile{Data: []byte("{{left as-is}}")},
+		"{{.Name}}/ver.txt.tmpl": &fstest.MapFile{Data: []byte("{{.Port}}")},
+	}
+
+	installDir := t.TempDir()
+	data := templateData{Dir: installDir, Port: 8080, Name: "svc"}
+	report, err := skeletonfs.SkeletonFS(skel,
+		skeletonfs.WithTemplateData(data),
+		skeletonfs.WithTemplateFuncs(template.FuncMap{"upper": strings.ToUpper}),
+	).InstallWithReport(installDir)
+	require.NoError(t, err)
+
+	for path, expected := range map[string]string{
+		"etc/app.conf": "dir=" + installDir + " port=8080 name=SVC",
+		"svc/data.txt": "{{left as-is}}",
+		"svc/ver.txt":  "8080",
+	} {
+		actual, err := os.ReadFile(filepath.Join(installDir, path))
+		if assert.NoError(t, err) {
+			assert.Equalf(t, expected, string(actual), "for %q", path)
+		}
+	}
+	_, err = os.Stat(filepath.Join(installDir, "etc", "app.conf.tmpl"))
+	assert.True(t, os.IsNotExist(err), "should strip the suffix")
+
+	for _, e := range report.Entries {
+		if e.Path == "svc/ver.txt" {
+			assert.Equal(t, "{{.Name}}/ver.txt.tmpl", e.Source)
+		}
+	}
+}
+
+func TestSkeletonFS_templatePattern(t *testing.T) {
+	skel := fstest.MapFS{
+		"app.conf": &fstest.MapFile{Data: []byte("port={{.}}")},
+		"app.tmpl": &fstest.MapFile{Data: []byte("{{.}}")},
+	}
+
+	installDir := t.TempDir()
+	require.NoError(t, skeletonfs.SkeletonFS(skel,
+		skeletonfs.WithTemplateData(8080),
+		skeletonfs.WithTemplatePattern("*.conf"),
+	).Install(installDir))
+
+	actual, err := os.ReadFile(filepath.Join(installDir, "app.conf"))
+	require.NoError(t, err)
+	assert.Equal(t, "port=8080", string(actual))
+	actual, err = os.ReadFile(filepath.Join(installDir, "app.tmpl"))
+	require.NoError(t, err)
+	assert.Equal(t, "{{.}}", string(actual), "should only render the pattern")
+}
+
+func TestSkeletonFS_templateErrors(t *testing.T) {
+	for path, file := range map[string]string{
+		"syntax.tmpl":   "{{.Port",
+		"missing.tmpl":  "{{.Missing}}",
+		"{{.Missing}}/": "",
+	} {
+		skel := fstest.MapFS{path: &fstest.MapFile{Data: []byte(file)}}
+
+		err := skeletonfs.SkeletonFS(skel, skeletonfs.WithTemplateData(map[string]int{"Port": 1})).Install(t.TempDir())
+		var installErr skeletonfs.SkeletonInstallError
+		if assert.ErrorAsf(t, err, &installErr, "for %q", path) {
+			assert.Equal(t, strings.TrimSuffix(path, "/"), installErr.Path)
+			assert.Contains(t, err.Error(), "template")
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package skeletonfs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+	"testing/fstest"
+)
+
+const (
+	// DefaultTreeFilePerm is the permission of Tree files without a Mode.
+	DefaultTreeFilePerm fs.FileMode = 0644
+	// DefaultTreeDirPerm is the permission of Tree directories without a Mode.
+	DefaultTreeDirPerm fs.FileMode = 0755
+)
+
+// Tree is a concise skeleton literal, mapping slash separated paths to their
+// contents:
+//
+//	skeletonfs.Tree{
+//		"etc/app.conf": "port=8080\n",
+//		"bin/app":      skeletonfs.File{Contents: script, Mode: 0755},
+//		"var/log":      skeletonfs.Dir{},
+//		"current":      skeletonfs.Symlink("releases/v2"),
+//		"releases/v2":  skeletonfs.Tree{"VERSION": "v2"},
+//		"data.bin":     func(w io.Writer) { w.Write(generated) },
+//	}
+//
+// Contents may be a string, []byte, func(io.Writer), File, Dir, Symlink, or a
+// nested Tree for a directory. Parent directories are created as needed.
+type Tree map[string]any
+
+// File is a Tree file with a Mode. Its Contents may be a string, []byte, or
+// func(io.Writer).
+type File struct {
+	Contents any
+	Mode     fs.FileMode
+}
+
+// Dir is a Tree directory with a Mode, which may be empty.
+type Dir struct {
+	Mode fs.FileMode
+}
+
+// Symlink is a Tree symlink to the target.
+type Symlink string
+
+// Extend returns a new Tree with the overrides layered over the tree.
+// Directories are merged, while other paths in the overrides replace those in
+// the tree, including anything under them.
+func (t Tree) Extend(overrides Tree) Tree {
+	extended := t.flatten()
+	flatOverrides := overrides.flatten()
+	for p, contents := range flatOverrides {
+		existing, exists := extended[p]
+		if _, isDir := contents.(Dir); isDir {
+			if _, wasDir := existing.(Dir); wasDir && contents.(Dir).Mode == 0 {
+				// keep the mode of the directory being extended
+				continue
+			}
+		} else {
+			for child := range extended {
+				if strings.HasPrefix(child, p+"/") {
+					delete(extended, child)
+				}
+			}
+		}
+		if exists {
+			delete(extended, p)
+		}
+		// replace anything in the way of the override's parents
+		for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			if parent, ok := extended[dir]; ok {
+				if _, isDir := parent.(Dir); !isDir {
+					delete(extended, dir)
+				}
+			}
+		}
+	}
+	for p, contents := range flatOverrides {
+		if _, kept := extended[p]; kept {
+			continue
+		}
+		extended[p] = contents
+	}
+	return extended
+}
+
+// flatten expands nested Trees into their full paths, leaving their
+// directories as Dirs.
+func (t Tree) flatten() Tree {
+	flat := Tree{}
+	for p, contents := range t {
+		p = path.Clean(strings.Trim(p, "/"))
+		nested, ok := contents.(Tree)
+		if !ok {
+			flat[p] = contents
+			continue
+		}
+		flat[p] = Dir{}
+		for np, ncontents := range nested.flatten() {
+			flat[path.Join(p, np)] = ncontents
+		}
+	}
+	return flat
+}
+
+// FS builds the tree into a file system, that can be used with SkeletonFS. It
+// panics if any contents are invalid, as a Tree is written in place in tests.
+func (t Tree) FS() ReadLinkFS {
+	fsys, err := t.build()
+	if err != nil {
+		panic(fmt.Sprintf("skeletonfs: tree: %v", err))
+	}
+	return fsys
+}
+
+func (t Tree) build() (ReadLinkFS, error) {
+	flat := t.flatten()
+	paths := make([]string, 0, len(flat))
+	for p := range flat {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+
+	mapFS := fstest.MapFS{}
+	for _, p := range paths {
+		if !fs.ValidPath(p) || p == "." {
+			return nil, fmt.Errorf("invalid path %q", p)
+		}
+
+		file, err := treeFile(flat[p])
+		if err != nil {
+			return nil, fmt.Errorf("path %q: %w", p, err)
+		}
+		mapFS[p] = file
+	}
+
+	// parents are implied by MapFS, but need to be directories.
+	for _, p := range paths {
+		for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			if parent, ok := mapFS[dir]; ok && !parent.Mode.IsDir() {
+				return nil, fmt.Errorf("path %q: parent %q is not a directory", p, dir)
+			}
+		}
+	}
+
+	return linkMapFS{mapFS}, nil
+}
+
+func treeFile(contents any) (*fstest.MapFile, error) {
+	switch c := contents.(type) {
+	case Dir:
+		return &fstest.MapFile{Mode: fs.ModeDir | permOr(c.Mode, DefaultTreeDirPerm)}, nil
+	case Symlink:
+		return &fstest.MapFile{Data: []byte(c), Mode: fs.ModeSymlink | 0777}, nil
+	case File:
+		data, err := treeData(c.Contents)
+		if err != nil {
+			return nil, err
+		}
+		return &fstest.MapFile{Data: data, Mode: permOr(c.Mode, DefaultTreeFilePerm)}, nil
+	}
+
+	data, err := treeData(contents)
+	if err != nil {
+		return nil, err
+	}
+	return &fstest.MapFile{Data: data, Mode: DefaultTreeFilePerm}, nil
+}
+
+func treeData(contents any) ([]byte, error) {
+	switch c := contents.(type) {
+	case string:
+		return []byte(c), nil
+	case []byte:
+		return c, nil
+	case func(io.Writer):
+		var buf bytes.Buffer
+		c(&buf)
+		return buf.Bytes(), nil
+	}
+	return nil, fmt.Errorf("unsupported contents %T", contents)
+}
+
+func permOr(mode, fallback fs.FileMode) fs.FileMode {
+	if mode.Perm() == 0 {
+		return fallback
+	}
+	return mode.Perm()
+}
+
+// linkMapFS is a fstest.MapFS exposing its symlinks, whose targets are their
+// Data.
+type linkMapFS struct {
+	fstest.MapFS
+}
+
+func (fsys linkMapFS) ReadLink(name string) (string, error) {
+	f, ok := fsys.MapFS[name]
+	if !ok {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrNotExist}
+	}
+	if !isSymlink(f.Mode) {
+		return "", &fs.PathError{Op: "readlink", Path: name, Err: fs.ErrInvalid}
+	}
+	return string(f.Data), nil
+}
+
+func (fsys linkMapFS) Lstat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrInvalid}
+	}
+	if name == "." {
+		return fs.Stat(fsys.MapFS, name)
+	}
+
+	// directory entries describe the symlinks themselves.
+	entries, err := fs.ReadDir(fsys.MapFS, path.Dir(name))
+	if err != nil {
+		return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrNotExist}
+	}
+	for _, entry := range entries {
+		if entry.Name() == path.Base(name) {
+			return entry.Info()
+		}
+	}
+	return nil, &fs.PathError{Op: "lstat", Path: name, Err: fs.ErrNotExist}
+}
@@ -0,0 +1,92 @@
+package skeletonfs_test
+
+import (
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/jahkeup/testthings/skeletonfs"
+)
+
+func TestTree(t *testing.T) {
+	tree := skeletonfs.Tree{
+		"etc/app.conf": "port=8080\n",
+		"bin/app":      skeletonfs.File{Contents: []byte("#!/bin/sh\n"), Mode: 0750},
+		"var/log":      skeletonfs.Dir{},
+		"current":      skeletonfs.Symlink("releases/v2"),
+		"releases/v2":  skeletonfs.Tree{"VERSION": "v2"},
+		"data.csv": func(w io.Writer) {
+			for i := 0; i < 3; i++ {
+				fmt.Fprintf(w, "%d\n", i)
+			}
+		},
+	}
+	fsys := tree.FS()
+
+	info, err := fsys.Lstat("current")
+	require.NoError(t, err)
+	assert.Equal(t, fs.ModeSymlink, info.Mode().Type())
+	target, err := fsys.ReadLink("current")
+	require.NoError(t, err)
+	assert.Equal(t, "releases/v2", target)
+
+	installDir := skeletonfs.SkeletonFS(fsys).InstallTemp(t)
+	skeletonfs.AssertTree(t, installDir, fsys)
+
+	data, err := os.ReadFile(filepath.Join(installDir, "data.csv"))
+	require.NoError(t, err)
+	assert.Equal(t, "0\n1\n2\n", string(data))
+	data, err = os.ReadFile(filepath.Join(installDir, "current", "VERSION"))
+	require.NoError(t, err)
+	assert.Equal(t, "v2", string(data))
+
+	info, err = os.Stat(filepath.Join(installDir, "bin", "app"))
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0750), info.Mode().Perm())
+	info, err = os.Stat(filepath.Join(installDir, "var", "log"))
+	require.NoError(t, err)
+	assert.True(t, info.IsDir())
+}
+
+func TestTree_Extend(t *testing.T) {
+	base := skeletonfs.Tree{
+		"etc":          skeletonfs.Dir{Mode: 0700},
+		"etc/app.conf": "base",
+		"etc/db.conf":  "db",
+		"lib/plugins":  skeletonfs.Tree{"a.so": "a", "b.so": "b"},
+		"run":          "not a dir",
+	}
+	extended := base.Extend(skeletonfs.Tree{
+		"etc":         skeletonfs.Tree{"app.conf": "override"},
+		"lib/plugins": "replaced",
+		"run/app.pid": "1",
+	})
+
+	installDir := skeletonfs.SkeletonFS(extended.FS()).InstallTemp(t)
+	skeletonfs.AssertTree(t, installDir, skeletonfs.Tree{
+		"etc/app.conf": "override",
+		"etc/db.conf":  "db",
+		"lib/plugins":  "replaced",
+		"run/app.pid":  "1",
+	}.FS())
+
+	info, err := os.Stat(filepath.Join(installDir, "etc"))
+	require.NoError(t, err)
+	assert.Equal(t, fs.FileMode(0700), info.Mode().Perm(), "should keep the extended dir's mode")
+	assert.Len(t, base, 5, "should not modify the extended tree")
+}
+
+func TestTree_invalid(t *testing.T) {
+	assert.PanicsWithValue(t, `skeletonfs: tree: path "a": unsupported contents int`, func() {
+		skeletonfs.Tree{"a": 1}.FS()
+	})
+	assert.PanicsWithValue(t, `skeletonfs: tree: path "a/b": parent "a" is not a directory`, func() {
+		skeletonfs.Tree{"a": "file", "a/b": "file"}.FS()
+	})
+}
@@ -0,0 +1,122 @@
+package skeletonfs
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"io/fs"
+	"strings"
+	"testing/fstest"
+)
+
+// ReadTxtar reads the txtar archive, the plain text format used by the Go
+// tool's tests, as a skeleton. The format only carries regular files, which
+// are given the DefaultTreeFilePerm.
+//
+// An archive is a comment followed by files, each introduced by a marker line
+// with its name:
+//
+//	comment
+//	-- etc/app.conf --
+//	port=8080
+//	-- VERSION --
+//	v2
+func ReadTxtar(data []byte) (ReadLinkFS, error) {
+	mapFS := fstest.MapFS{}
+	for _, file := range parseTxtar(data) {
+		name, err := archiveName(file.name)
+		if err != nil {
+			return nil, err
+		}
+		mapFS[name] = &fstest.MapFile{Data: file.data, Mode: DefaultTreeFilePerm}
+	}
+	return linkMapFS{mapFS}, nil
+}
+
+// WriteTxtar writes the regular files in fsys as a txtar archive, preceded by
+// the comment. Directories are implied by the files within them. Symlinks, and
+// files with content the format can't carry exactly, are errors: a line that's
+// a file marker, or a missing final newline.
+func WriteTxtar(w io.Writer, fsys fs.FS, comment string) error {
+	var buf bytes.Buffer
+	if comment != "" {
+		buf.WriteString(comment)
+		if !strings.HasSuffix(comment, "\n") {
+			buf.WriteByte('\n')
+		}
+	}
+
+	err := fs.WalkDir(fsys, ".", func(p string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		switch {
+		case d.IsDir():
+			return nil
+		case !d.Type().IsRegular():
+			return fmt.Errorf("txtar: %q: unsupported file type: %v", p, d.Type())
+		}
+
+		data, err := fs.ReadFile(fsys, p)
+		if err != nil {
+			return err
+		}
+		for _, line := range bytes.SplitAfter(data, []byte("\n")) {
+			if _, ok := txtarMarker(line); ok {
+				return fmt.Errorf("txtar: %q: contains a file marker line %q", p, bytes.TrimSpace(line))
+			}
+		}
+
+		if len(data) > 0 && data[len(data)-1] != '\n' {
+			return fmt.Errorf("txtar: %q: no final newline", p)
+		}
+
+		fmt.Fprintf(&buf, "-- %s --\n", p)
+		buf.Write(data)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = w.Write(buf.Bytes())
+	return err
+}
+
+type txtarFile struct {
+	name string
+	data []byte
+}
+
+// parseTxtar splits the archive into its files, dropping the comment.
+func parseTxtar(data []byte) []txtarFile {
+	var files []txtarFile
+	var current *txtarFile
+	for len(data) > 0 {
+		line := data
+		if i := bytes.IndexByte(data, '\n'); i >= 0 {
+			line = data[:i+1]
+		}
+		data = data[len(line):]
+
+		if name, ok := txtarMarker(line); ok {
+			files = append(files, txtarFile{name: name, data: []byte{}})
+			current = &files[len(files)-1]
+			continue
+		}
+		if current != nil {
+			current.data = append(current.data, line...)
+		}
+	}
+	return files
+}
+
+// txtarMarker returns the name in the marker line, like "-- name --".
+func txtarMarker(line []byte) (string, bool) {
+	text := strings.TrimRight(string(line), "\r\n")
+	if !strings.HasPrefix(text, "-- ") || !strings.HasSuffix(text, " --") || len(text) < len("-- x --") {
+		return "", false
+	}
+	name := strings.TrimSpace(text[len("-- ") : len(text)-len(" --")])
+	return name, name != ""
+}
@@ -0,0 +1,88 @@
+package testerr
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/jahkeup/testthings"
+)
+
+// CanceledContext creates a context, cleaned up with the test, that has
+// already been cancelled with the cause. Its Err is context.Canceled and
+// context.Cause returns cause (or context.Canceled when cause is nil).
+func CanceledContext(testingT testthings.Cleanuper, cause error) context.Context {
+	parent, _ := testthings.NewContext(testingT)
+	ctx, cancel := context.WithCancelCause(parent)
+	cancel(cause)
+	return ctx
+}
+
+// ExpiredContext creates a context, cleaned up with the test, whose deadline
+// has already passed. Its Err is context.DeadlineExceeded and context.Cause
+// returns cause (or context.DeadlineExceeded when cause is nil).
+func ExpiredContext(testingT testthings.Cleanuper, cause error) context.Context {
+	parent, _ := testthings.NewContext(testingT)
+	ctx, cancel := context.WithDeadlineCause(parent, time.Now(), cause)
+	testingT.Cleanup(cancel)
+	return ctx
+}
+
+// HTTPError creates the error returned by an net/http Client when the request
+// fails with err.
+func HTTPError(method, rawURL string, err error) *url.Error {
+	return &url.Error{
+		Op:  httpOp(method),
+		URL: rawURL,
+		Err: err,
+	}
+}
+
+// HTTPCanceled creates the error returned by an net/http Client when the
+// request's context is cancelled.
+func HTTPCanceled(method, rawURL string) *url.Error {
+	return HTTPError(method, rawURL, context.Canceled)
+}
+
+// HTTPDeadlineExceeded creates the error returned by an net/http Client when
+// the request's context passes its deadline.
+func HTTPDeadlineExceeded(method, rawURL string) *url.Error {
+	return HTTPError(method, rawURL, context.DeadlineExceeded)
+}
+
+// HTTPClientTimeout creates the error returned by an net/http Client when its
+// Timeout is exceeded. Like the real error, it's a timeout that matches
+// context.DeadlineExceeded.
+func HTTPClientTimeout(method, rawURL string) *url.Error {
+	return HTTPError(method, rawURL, clientTimeoutError{})
+}
+
+// DriverError wraps err the way database drivers commonly report context
+// errors. database/sql itself returns the context's error as is, so use the
+// context errors directly to fake those.
+func DriverError(driverName string, err error) error {
+	return fmt.Errorf("%s: %w", driverName, err)
+}
+
+// httpOp formats the method the way net/http does for its url.Error.
+func httpOp(method string) string {
+	if method == "" {
+		return "Get"
+	}
+	return method[:1] + strings.ToLower(method[1:])
+}
+
+// clientTimeoutError mirrors net/http's (unexported) Client timeout error.
+type clientTimeoutError struct{}
+
+func (clientTimeoutError) Error() string {
+	return "context deadline exceeded (Client.Timeout exceeded while awaiting headers)"
+}
+
+func (clientTimeoutError) Timeout() bool { return true }
+
+func (clientTimeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
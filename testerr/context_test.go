@@ -0,0 +1,86 @@
+package testerr_test
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jahkeup/testthings/testerr"
+)
+
+func TestContexts(t *testing.T) {
+	t.Run("canceled", func(t *testing.T) {
+		ctx := testerr.CanceledContext(t, testerr.Expected)
+		<-ctx.Done()
+		assert.ErrorIs(t, ctx.Err(), context.Canceled)
+		assert.ErrorIs(t, context.Cause(ctx), testerr.Expected)
+	})
+
+	t.Run("canceled without cause", func(t *testing.T) {
+		ctx := testerr.CanceledContext(t, nil)
+		assert.ErrorIs(t, context.Cause(ctx), context.Canceled)
+	})
+
+	t.Run("expired", func(t *testing.T) {
+		ctx := testerr.ExpiredContext(t, testerr.Expected)
+		<-ctx.Done()
+		assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded)
+		assert.NotErrorIs(t, ctx.Err(), context.Canceled)
+		assert.ErrorIs(t, context.Cause(ctx), testerr.Expected)
+
+		_, hasDeadline := ctx.Deadline()
+		assert.True(t, hasDeadline)
+	})
+
+	t.Run("expired without cause", func(t *testing.T) {
+		ctx := testerr.ExpiredContext(t, nil)
+		assert.ErrorIs(t, context.Cause(ctx), context.DeadlineExceeded)
+	})
+}
+
+func TestContextErrors(t *testing.T) {
+	testcases := map[string]struct {
+		err     error
+		is      error
+		timeout bool
+		message string
+	}{
+		"http canceled": {
+			err:     testerr.HTTPCanceled("GET", "http://example.com"),
+			is:      context.Canceled,
+			message: `Get "http://example.com": context canceled`,
+		},
+		"http deadline": {
+			err:     testerr.HTTPDeadlineExceeded("POST", "http://example.com"),
+			is:      context.DeadlineExceeded,
+			timeout: true,
+			message: `Post "http://example.com": context deadline exceeded`,
+		},
+		"http client timeout": {
+			err:     testerr.HTTPClientTimeout("GET", "http://example.com"),
+			is:      context.DeadlineExceeded,
+			timeout: true,
+			message: `Get "http://example.com": context deadline exceeded (Client.Timeout exceeded while awaiting headers)`,
+		},
+		"driver": {
+			err:     testerr.DriverError("pq", context.Canceled),
+			is:      context.Canceled,
+			message: "pq: context canceled",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualError(t, tc.err, tc.message)
+			assert.ErrorIs(t, tc.err, tc.is)
+
+			var urlErr *url.Error
+			if errors.As(tc.err, &urlErr) {
+				assert.Equal(t, tc.timeout, urlErr.Timeout())
+			}
+		})
+	}
+}
@@ -0,0 +1,48 @@
+package testerr
+
+import (
+	"io/fs"
+	"os"
+	"syscall"
+)
+
+// PathError creates a *fs.PathError as returned by os and io/fs operations.
+func PathError(op, path string, err error) *fs.PathError {
+	return &fs.PathError{Op: op, Path: path, Err: err}
+}
+
+// NotExist creates the error returned when path doesn't exist. It matches
+// fs.ErrNotExist.
+func NotExist(op, path string) *fs.PathError {
+	return PathError(op, path, syscall.ENOENT)
+}
+
+// Exist creates the error returned when path already exists. It matches
+// fs.ErrExist.
+func Exist(op, path string) *fs.PathError {
+	return PathError(op, path, syscall.EEXIST)
+}
+
+// Permission creates the error returned when access to path is denied. It
+// matches fs.ErrPermission.
+func Permission(op, path string) *fs.PathError {
+	return PathError(op, path, syscall.EACCES)
+}
+
+// NoSpace creates the error returned when writing to path fails due to lack of
+// space on the device.
+func NoSpace(path string) *fs.PathError {
+	return PathError("write", path, syscall.ENOSPC)
+}
+
+// ReadOnly creates the error returned when modifying path on a read-only
+// filesystem.
+func ReadOnly(op, path string) *fs.PathError {
+	return PathError(op, path, syscall.EROFS)
+}
+
+// FileDeadline creates the error returned when an operation on the file at
+// path passes its deadline. It matches os.ErrDeadlineExceeded.
+func FileDeadline(op, path string) *fs.PathError {
+	return PathError(op, path, os.ErrDeadlineExceeded)
+}
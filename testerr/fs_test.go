@@ -0,0 +1,75 @@
+package testerr_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jahkeup/testthings/testerr"
+)
+
+func TestPathErrors(t *testing.T) {
+	testcases := map[string]struct {
+		err     *fs.PathError
+		is      error
+		message string
+	}{
+		"not exist": {
+			err:     testerr.NotExist("open", "/nope"),
+			is:      fs.ErrNotExist,
+			message: "open /nope: no such file or directory",
+		},
+		"exist": {
+			err:     testerr.Exist("mkdir", "/here"),
+			is:      fs.ErrExist,
+			message: "mkdir /here: file exists",
+		},
+		"permission": {
+			err:     testerr.Permission("open", "/secret"),
+			is:      fs.ErrPermission,
+			message: "open /secret: permission denied",
+		},
+		"no space": {
+			err:     testerr.NoSpace("/full"),
+			is:      syscall.ENOSPC,
+			message: "write /full: no space left on device",
+		},
+		"read only": {
+			err:     testerr.ReadOnly("unlink", "/ro"),
+			is:      syscall.EROFS,
+			message: "unlink /ro: read-only file system",
+		},
+		"deadline": {
+			err:     testerr.FileDeadline("read", "/pipe"),
+			is:      os.ErrDeadlineExceeded,
+			message: "read /pipe: i/o timeout",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualError(t, tc.err, tc.message)
+			assert.ErrorIs(t, tc.err, tc.is)
+
+			var pathErr *fs.PathError
+			assert.ErrorAs(t, error(tc.err), &pathErr)
+		})
+	}
+}
+
+func TestPathErrors_real(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nope")
+
+	_, realErr := os.Open(path)
+	fakeErr := testerr.NotExist("open", path)
+	if !errors.Is(realErr, syscall.ENOENT) {
+		t.Skipf("real error is platform specific: %v", realErr)
+	}
+
+	assert.Equal(t, realErr, error(fakeErr))
+}
@@ -0,0 +1,143 @@
+package testerr
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"os"
+	"syscall"
+)
+
+// NetError is a net.Error with configurable Timeout and Temporary behavior.
+type NetError struct {
+	Msg         string
+	IsTimeout   bool
+	IsTemporary bool
+}
+
+var _ net.Error = NetError{}
+
+// Error implements error.
+func (e NetError) Error() string {
+	if e.Msg != "" {
+		return e.Msg
+	}
+	return "testing net error"
+}
+
+// Timeout implements net.Error.
+func (e NetError) Timeout() bool { return e.IsTimeout }
+
+// Temporary implements net.Error.
+func (e NetError) Temporary() bool { return e.IsTemporary }
+
+// timeoutError mirrors the net package's (unexported) error for dial and DNS
+// timeouts, which also matches context.DeadlineExceeded.
+type timeoutError struct{}
+
+var _ net.Error = timeoutError{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+func (timeoutError) Is(target error) bool {
+	return target == context.DeadlineExceeded
+}
+
+// OpError creates a *net.OpError as returned by net.Conn and net.Dialer
+// operations.
+func OpError(op, network, addr string, err error) *net.OpError {
+	return &net.OpError{
+		Op:   op,
+		Net:  network,
+		Addr: netAddr(network, addr),
+		Err:  err,
+	}
+}
+
+// DialTimeout creates the error returned when dialing addr times out.
+func DialTimeout(network, addr string) *net.OpError {
+	return OpError("dial", network, addr, timeoutError{})
+}
+
+// DialRefused creates the error returned when dialing addr is refused.
+func DialRefused(network, addr string) *net.OpError {
+	return OpError("dial", network, addr, os.NewSyscallError("connect", syscall.ECONNREFUSED))
+}
+
+// ReadTimeout creates the error returned when reading from a connection to
+// addr passes its deadline.
+func ReadTimeout(network, addr string) *net.OpError {
+	return OpError("read", network, addr, os.ErrDeadlineExceeded)
+}
+
+// WriteTimeout creates the error returned when writing to a connection to addr
+// passes its deadline.
+func WriteTimeout(network, addr string) *net.OpError {
+	return OpError("write", network, addr, os.ErrDeadlineExceeded)
+}
+
+// ConnReset creates the error returned when reading from a connection to addr
+// that was reset by the peer.
+func ConnReset(network, addr string) *net.OpError {
+	return OpError("read", network, addr, os.NewSyscallError("read", syscall.ECONNRESET))
+}
+
+// ConnClosed creates the error returned when using a connection to addr after
+// it has been closed.
+func ConnClosed(op, network, addr string) *net.OpError {
+	return OpError(op, network, addr, net.ErrClosed)
+}
+
+// DNSNotFound creates the error returned when host cannot be resolved.
+func DNSNotFound(host string) *net.DNSError {
+	return &net.DNSError{
+		Err:        "no such host",
+		Name:       host,
+		IsNotFound: true,
+	}
+}
+
+// DNSTimeout creates the error returned when resolving host times out.
+func DNSTimeout(host string) *net.DNSError {
+	return &net.DNSError{
+		Err:       timeoutError{}.Error(),
+		Name:      host,
+		IsTimeout: true,
+	}
+}
+
+// netAddr creates the net.Addr for the network, using the net package's types
+// where the address can be parsed (without resolving any names).
+func netAddr(network, addr string) net.Addr {
+	if addr == "" {
+		return nil
+	}
+
+	if ap, err := netip.ParseAddrPort(addr); err == nil {
+		switch network {
+		case "tcp", "tcp4", "tcp6":
+			return net.TCPAddrFromAddrPort(ap)
+		case "udp", "udp4", "udp6":
+			return net.UDPAddrFromAddrPort(ap)
+		}
+	}
+
+	switch network {
+	case "unix", "unixgram", "unixpacket":
+		return &net.UnixAddr{Name: addr, Net: network}
+	}
+
+	return fakeAddr{network: network, addr: addr}
+}
+
+type fakeAddr struct {
+	network string
+	addr    string
+}
+
+var _ net.Addr = fakeAddr{}
+
+func (a fakeAddr) Network() string { return a.network }
+func (a fakeAddr) String() string  { return a.addr }
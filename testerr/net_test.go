@@ -0,0 +1,113 @@
+package testerr_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jahkeup/testthings/testerr"
+)
+
+func TestNetErrors(t *testing.T) {
+	testcases := map[string]struct {
+		err     error
+		is      []error
+		isNot   []error
+		timeout bool
+		message string
+	}{
+		"dial timeout": {
+			err:     testerr.DialTimeout("tcp", "127.0.0.1:80"),
+			is:      []error{context.DeadlineExceeded},
+			timeout: true,
+			message: "dial tcp 127.0.0.1:80: i/o timeout",
+		},
+		"dial refused": {
+			err:     testerr.DialRefused("tcp", "127.0.0.1:80"),
+			is:      []error{syscall.ECONNREFUSED},
+			isNot:   []error{context.DeadlineExceeded},
+			message: "dial tcp 127.0.0.1:80: connect: connection refused",
+		},
+		"read timeout": {
+			err:     testerr.ReadTimeout("tcp", "[::1]:443"),
+			is:      []error{os.ErrDeadlineExceeded},
+			timeout: true,
+			message: "read tcp [::1]:443: i/o timeout",
+		},
+		"write timeout": {
+			err:     testerr.WriteTimeout("udp", "example.com:53"),
+			is:      []error{os.ErrDeadlineExceeded},
+			timeout: true,
+			message: "write udp example.com:53: i/o timeout",
+		},
+		"conn reset": {
+			err:     testerr.ConnReset("tcp", "127.0.0.1:80"),
+			is:      []error{syscall.ECONNRESET},
+			message: "read tcp 127.0.0.1:80: read: connection reset by peer",
+		},
+		"conn closed": {
+			err:     testerr.ConnClosed("write", "unix", "/tmp/sock"),
+			is:      []error{net.ErrClosed},
+			message: "write unix /tmp/sock: use of closed network connection",
+		},
+		"dns not found": {
+			err:     testerr.DNSNotFound("nope.invalid"),
+			message: "lookup nope.invalid: no such host",
+		},
+		"dns timeout": {
+			err:     testerr.DNSTimeout("slow.invalid"),
+			timeout: true,
+			message: "lookup slow.invalid: i/o timeout",
+		},
+		"net error": {
+			err:     testerr.NetError{Msg: "flaky", IsTimeout: true},
+			timeout: true,
+			message: "flaky",
+		},
+	}
+
+	for name, tc := range testcases {
+		t.Run(name, func(t *testing.T) {
+			assert.EqualError(t, tc.err, tc.message)
+			for _, target := range tc.is {
+				assert.ErrorIs(t, tc.err, target)
+			}
+			for _, target := range tc.isNot {
+				assert.NotErrorIs(t, tc.err, target)
+			}
+
+			var netErr net.Error
+			if assert.ErrorAs(t, tc.err, &netErr) {
+				assert.Equal(t, tc.timeout, netErr.Timeout(), "timeout")
+			}
+		})
+	}
+}
+
+func TestNetErrors_real(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("cannot listen: %v", err)
+	}
+	addr := l.Addr().String()
+	assert.NoError(t, l.Close())
+
+	_, realErr := net.Dial("tcp", addr)
+	if realErr == nil || !errors.Is(realErr, syscall.ECONNREFUSED) {
+		t.Skipf("dial to closed listener was not refused: %v", realErr)
+	}
+
+	fakeErr := testerr.DialRefused("tcp", addr)
+	assert.Equal(t, realErr.Error(), fakeErr.Error())
+
+	var realOp, fakeOp *net.OpError
+	if assert.ErrorAs(t, realErr, &realOp) && assert.ErrorAs(t, fakeErr, &fakeOp) {
+		assert.Equal(t, realOp.Addr.String(), fakeOp.Addr.String())
+		assert.Equal(t, realOp.Timeout(), fakeOp.Timeout())
+	}
+}
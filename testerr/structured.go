@@ -0,0 +1,157 @@
+package testerr
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+
+	"github.com/jahkeup/testthings"
+)
+
+// maxStackDepth limits the number of frames captured for a StructuredError.
+const maxStackDepth = 32
+
+// StructuredError is a TestingError that carries contextual fields along with
+// the stack it was created on. It still matches its token with errors.Is, so
+// callers checking for sentinels (like Expected) continue to work while the
+// error itself can tell you which fake produced it.
+//
+// Use %+v to format the error with its fields and stack.
+type StructuredError struct {
+	testthings.KV
+
+	// Token is the sentinel that this error matches with errors.Is.
+	Token TestingError
+	// Err is the (optional) underlying error.
+	Err error
+
+	stack []uintptr
+}
+
+var _ error = (*StructuredError)(nil)
+
+// New creates a StructuredError for the token with the given fields, capturing
+// the caller's stack.
+func New(token TestingError, fields testthings.KV) *StructuredError {
+	return newStructured(token, nil, fields)
+}
+
+// Wrap creates a StructuredError for the token wrapping err, capturing the
+// caller's stack.
+func Wrap(token TestingError, err error, fields testthings.KV) *StructuredError {
+	return newStructured(token, err, fields)
+}
+
+// With creates a StructuredError for the token with the given fields,
+// capturing the caller's stack.
+func (a TestingError) With(fields testthings.KV) *StructuredError {
+	return newStructured(a, nil, fields)
+}
+
+// newStructured must only be called by exported constructors: the captured
+// stack starts at the constructor's caller.
+func newStructured(token TestingError, err error, fields testthings.KV) *StructuredError {
+	pcs := make([]uintptr, maxStackDepth)
+	// skip runtime.Callers, newStructured, and the exported constructor
+	n := runtime.Callers(3, pcs)
+
+	kv := testthings.KV{}
+	for k, v := range fields {
+		kv[k] = v
+	}
+
+	return &StructuredError{
+		KV:    kv,
+		Token: token,
+		Err:   err,
+		stack: pcs[:n],
+	}
+}
+
+// Error implements error.
+func (e *StructuredError) Error() string {
+	msg := e.Token.Error()
+	if len(e.KV) > 0 {
+		msg += " (" + e.KV.Format("") + ")"
+	}
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Is reports whether the target is the error's token.
+func (e *StructuredError) Is(target error) bool {
+	token, ok := target.(TestingError)
+	return ok && token == e.Token
+}
+
+// Unwrap returns the underlying error, if any.
+func (e *StructuredError) Unwrap() error {
+	return e.Err
+}
+
+// Frames returns the stack frames captured when the error was created, the
+// first frame being the creator.
+func (e *StructuredError) Frames() []runtime.Frame {
+	var frames []runtime.Frame
+	if len(e.stack) == 0 {
+		return frames
+	}
+
+	iter := runtime.CallersFrames(e.stack)
+	for {
+		frame, more := iter.Next()
+		frames = append(frames, frame)
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// Caller returns the frame that created the error.
+func (e *StructuredError) Caller() runtime.Frame {
+	frames := e.Frames()
+	if len(frames) == 0 {
+		return runtime.Frame{}
+	}
+	return frames[0]
+}
+
+// Format implements fmt.Formatter. The %+v verb includes the error's fields,
+// one per line, and the stack it was created on.
+func (e *StructuredError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('+') {
+			io.WriteString(s, e.Token.Error())
+			for _, line := range e.KV.Strings(formatFieldLine) {
+				io.WriteString(s, "\n"+line)
+			}
+			if e.Err != nil {
+				fmt.Fprintf(s, "\ncaused by: %+v", e.Err)
+			}
+			io.WriteString(s, "\n"+formatFrames(e.Frames()))
+			return
+		}
+		io.WriteString(s, e.Error())
+	case 's':
+		io.WriteString(s, e.Error())
+	case 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		fmt.Fprintf(s, "%%!%c(%T=%s)", verb, e, e.Error())
+	}
+}
+
+const formatFieldLine = "\t%v: %v"
+
+func formatFrames(frames []runtime.Frame) string {
+	lines := make([]string, 0, len(frames))
+	for _, frame := range frames {
+		lines = append(lines, fmt.Sprintf("%s\n\t%s:%d", frame.Function, frame.File, frame.Line))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,52 @@
+package testerr_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/jahkeup/testthings"
+	"github.com/jahkeup/testthings/testerr"
+)
+
+type fakeReader struct{}
+
+func (fakeReader) Read([]byte) (int, error) {
+	return 0, testerr.Expected.With(testthings.KV{"fake": "reader"})
+}
+
+func TestStructuredError(t *testing.T) {
+	_, err := fakeReader{}.Read(nil)
+	wrapped := fmt.Errorf("layer two: %w", fmt.Errorf("layer one: %w", err))
+
+	assert.ErrorIs(t, wrapped, testerr.Expected)
+	assert.NotErrorIs(t, wrapped, testerr.Ignore)
+	assert.Equal(t, `layer two: layer one: this error is expected! (fake="reader")`, wrapped.Error())
+
+	var serr *testerr.StructuredError
+	if assert.ErrorAs(t, wrapped, &serr) {
+		assert.Equal(t, "reader", serr.KV["fake"])
+		assert.True(t, strings.HasSuffix(serr.Caller().Function, "fakeReader.Read"),
+			"should be created by the fake, not %q", serr.Caller().Function)
+	}
+
+	t.Run("verbose", func(t *testing.T) {
+		verbose := fmt.Sprintf("%+v", err)
+		t.Log(verbose)
+		assert.Contains(t, verbose, "\tfake: reader")
+		assert.Contains(t, verbose, "fakeReader.Read")
+		assert.Contains(t, verbose, "structured_test.go")
+	})
+
+	t.Run("wrap", func(t *testing.T) {
+		err := testerr.Wrap(testerr.HACK, io.ErrUnexpectedEOF, testthings.KV{"n": 1})
+		assert.ErrorIs(t, err, testerr.HACK)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Equal(t, `HACK: this is a hack (n="1"): unexpected EOF`, err.Error())
+		assert.True(t, errors.Is(err, err))
+	})
+}